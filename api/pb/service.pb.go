@@ -426,28 +426,1976 @@ func (x *UsersData) GetUsersData() []*UserData {
 	return nil
 }
 
+type UserError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uid           uint32                 `protobuf:"varint,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserError) Reset() {
+	*x = UserError{}
+	mi := &file_proto_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserError) ProtoMessage() {}
+
+func (x *UserError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserError.ProtoReflect.Descriptor instead.
+func (*UserError) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UserError) GetUid() uint32 {
+	if x != nil {
+		return x.Uid
+	}
+	return 0
+}
+
+func (x *UserError) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SyncUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Errors        []*UserError           `protobuf:"bytes,1,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SyncUsersResponse) Reset() {
+	*x = SyncUsersResponse{}
+	mi := &file_proto_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncUsersResponse) ProtoMessage() {}
+
+func (x *SyncUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncUsersResponse.ProtoReflect.Descriptor instead.
+func (*SyncUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SyncUsersResponse) GetErrors() []*UserError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type RepopulateUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Added         uint32                 `protobuf:"varint,1,opt,name=added,proto3" json:"added,omitempty"`
+	Removed       uint32                 `protobuf:"varint,2,opt,name=removed,proto3" json:"removed,omitempty"`
+	Changed       uint32                 `protobuf:"varint,3,opt,name=changed,proto3" json:"changed,omitempty"`
+	Errors        []*UserError           `protobuf:"bytes,4,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RepopulateUsersResponse) Reset() {
+	*x = RepopulateUsersResponse{}
+	mi := &file_proto_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RepopulateUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RepopulateUsersResponse) ProtoMessage() {}
+
+func (x *RepopulateUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RepopulateUsersResponse.ProtoReflect.Descriptor instead.
+func (*RepopulateUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RepopulateUsersResponse) GetAdded() uint32 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+func (x *RepopulateUsersResponse) GetRemoved() uint32 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+func (x *RepopulateUsersResponse) GetChanged() uint32 {
+	if x != nil {
+		return x.Changed
+	}
+	return 0
+}
+
+func (x *RepopulateUsersResponse) GetErrors() []*UserError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type UserTraffic struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Uid     uint32                 `protobuf:"varint,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	Backend string                 `protobuf:"bytes,2,opt,name=backend,proto3" json:"backend,omitempty"`
+	// empty when the core can't attribute the traffic to a single inbound
+	InboundTag    string `protobuf:"bytes,3,opt,name=inbound_tag,json=inboundTag,proto3" json:"inbound_tag,omitempty"`
+	Uplink        uint64 `protobuf:"varint,4,opt,name=uplink,proto3" json:"uplink,omitempty"`
+	Downlink      uint64 `protobuf:"varint,5,opt,name=downlink,proto3" json:"downlink,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserTraffic) Reset() {
+	*x = UserTraffic{}
+	mi := &file_proto_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserTraffic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserTraffic) ProtoMessage() {}
+
+func (x *UserTraffic) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserTraffic.ProtoReflect.Descriptor instead.
+func (*UserTraffic) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *UserTraffic) GetUid() uint32 {
+	if x != nil {
+		return x.Uid
+	}
+	return 0
+}
+
+func (x *UserTraffic) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *UserTraffic) GetInboundTag() string {
+	if x != nil {
+		return x.InboundTag
+	}
+	return ""
+}
+
+func (x *UserTraffic) GetUplink() uint64 {
+	if x != nil {
+		return x.Uplink
+	}
+	return 0
+}
+
+func (x *UserTraffic) GetDownlink() uint64 {
+	if x != nil {
+		return x.Downlink
+	}
+	return 0
+}
+
 type UsersStats struct {
-	state         protoimpl.MessageState  `protogen:"open.v1"`
-	UsersStats    []*UsersStats_UserStats `protobuf:"bytes,1,rep,name=users_stats,json=usersStats,proto3" json:"users_stats,omitempty"`
+	state        protoimpl.MessageState  `protogen:"open.v1"`
+	UsersStats   []*UsersStats_UserStats `protobuf:"bytes,1,rep,name=users_stats,json=usersStats,proto3" json:"users_stats,omitempty"`
+	UsersTraffic []*UserTraffic          `protobuf:"bytes,2,rep,name=users_traffic,json=usersTraffic,proto3" json:"users_traffic,omitempty"`
+	// acknowledges this collection when sent back in CollectUsersStatsRequest
+	Cursor        string `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsersStats) Reset() {
+	*x = UsersStats{}
+	mi := &file_proto_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsersStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsersStats) ProtoMessage() {}
+
+func (x *UsersStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsersStats.ProtoReflect.Descriptor instead.
+func (*UsersStats) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UsersStats) GetUsersStats() []*UsersStats_UserStats {
+	if x != nil {
+		return x.UsersStats
+	}
+	return nil
+}
+
+func (x *UsersStats) GetUsersTraffic() []*UserTraffic {
+	if x != nil {
+		return x.UsersTraffic
+	}
+	return nil
+}
+
+func (x *UsersStats) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type CollectUsersStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AckCursor     *string                `protobuf:"bytes,1,opt,name=ack_cursor,json=ackCursor,proto3,oneof" json:"ack_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CollectUsersStatsRequest) Reset() {
+	*x = CollectUsersStatsRequest{}
+	mi := &file_proto_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CollectUsersStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CollectUsersStatsRequest) ProtoMessage() {}
+
+func (x *CollectUsersStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CollectUsersStatsRequest.ProtoReflect.Descriptor instead.
+func (*CollectUsersStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CollectUsersStatsRequest) GetAckCursor() string {
+	if x != nil && x.AckCursor != nil {
+		return *x.AckCursor
+	}
+	return ""
+}
+
+type StreamUsersStatsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	IntervalSeconds uint32                 `protobuf:"varint,1,opt,name=interval_seconds,json=intervalSeconds,proto3" json:"interval_seconds,omitempty"`
+	// cursor of the last applied message; also acknowledges it
+	ResumeCursor  *string `protobuf:"bytes,2,opt,name=resume_cursor,json=resumeCursor,proto3,oneof" json:"resume_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamUsersStatsRequest) Reset() {
+	*x = StreamUsersStatsRequest{}
+	mi := &file_proto_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamUsersStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamUsersStatsRequest) ProtoMessage() {}
+
+func (x *StreamUsersStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamUsersStatsRequest.ProtoReflect.Descriptor instead.
+func (*StreamUsersStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *StreamUsersStatsRequest) GetIntervalSeconds() uint32 {
+	if x != nil {
+		return x.IntervalSeconds
+	}
+	return 0
+}
+
+func (x *StreamUsersStatsRequest) GetResumeCursor() string {
+	if x != nil && x.ResumeCursor != nil {
+		return *x.ResumeCursor
+	}
+	return ""
+}
+
+type AckUsersStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cursor        string                 `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AckUsersStatsRequest) Reset() {
+	*x = AckUsersStatsRequest{}
+	mi := &file_proto_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AckUsersStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AckUsersStatsRequest) ProtoMessage() {}
+
+func (x *AckUsersStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AckUsersStatsRequest.ProtoReflect.Descriptor instead.
+func (*AckUsersStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AckUsersStatsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type LogLine struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Line          string                 `protobuf:"bytes,1,opt,name=line,proto3" json:"line,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogLine) Reset() {
+	*x = LogLine{}
+	mi := &file_proto_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogLine) ProtoMessage() {}
+
+func (x *LogLine) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogLine.ProtoReflect.Descriptor instead.
+func (*LogLine) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *LogLine) GetLine() string {
+	if x != nil {
+		return x.Line
+	}
+	return ""
+}
+
+type BackendConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Configuration string                 `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
+	ConfigFormat  ConfigFormat           `protobuf:"varint,2,opt,name=config_format,json=configFormat,proto3,enum=api.ConfigFormat" json:"config_format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackendConfig) Reset() {
+	*x = BackendConfig{}
+	mi := &file_proto_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackendConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackendConfig) ProtoMessage() {}
+
+func (x *BackendConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackendConfig.ProtoReflect.Descriptor instead.
+func (*BackendConfig) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BackendConfig) GetConfiguration() string {
+	if x != nil {
+		return x.Configuration
+	}
+	return ""
+}
+
+func (x *BackendConfig) GetConfigFormat() ConfigFormat {
+	if x != nil {
+		return x.ConfigFormat
+	}
+	return ConfigFormat_PLAIN
+}
+
+type BackendLogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BackendName   string                 `protobuf:"bytes,1,opt,name=backend_name,json=backendName,proto3" json:"backend_name,omitempty"`
+	IncludeBuffer bool                   `protobuf:"varint,2,opt,name=include_buffer,json=includeBuffer,proto3" json:"include_buffer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BackendLogsRequest) Reset() {
+	*x = BackendLogsRequest{}
+	mi := &file_proto_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackendLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackendLogsRequest) ProtoMessage() {}
+
+func (x *BackendLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackendLogsRequest.ProtoReflect.Descriptor instead.
+func (*BackendLogsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BackendLogsRequest) GetBackendName() string {
+	if x != nil {
+		return x.BackendName
+	}
+	return ""
+}
+
+func (x *BackendLogsRequest) GetIncludeBuffer() bool {
+	if x != nil {
+		return x.IncludeBuffer
+	}
+	return false
+}
+
+type RestartBackendRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BackendName   string                 `protobuf:"bytes,1,opt,name=backend_name,json=backendName,proto3" json:"backend_name,omitempty"`
+	Config        *BackendConfig         `protobuf:"bytes,2,opt,name=config,proto3,oneof" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestartBackendRequest) Reset() {
+	*x = RestartBackendRequest{}
+	mi := &file_proto_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestartBackendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestartBackendRequest) ProtoMessage() {}
+
+func (x *RestartBackendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestartBackendRequest.ProtoReflect.Descriptor instead.
+func (*RestartBackendRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RestartBackendRequest) GetBackendName() string {
+	if x != nil {
+		return x.BackendName
+	}
+	return ""
+}
+
+func (x *RestartBackendRequest) GetConfig() *BackendConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type CoreRuntimeStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NumGoroutine  uint32                 `protobuf:"varint,1,opt,name=num_goroutine,json=numGoroutine,proto3" json:"num_goroutine,omitempty"`
+	NumGc         uint32                 `protobuf:"varint,2,opt,name=num_gc,json=numGc,proto3" json:"num_gc,omitempty"`
+	Alloc         uint64                 `protobuf:"varint,3,opt,name=alloc,proto3" json:"alloc,omitempty"`
+	TotalAlloc    uint64                 `protobuf:"varint,4,opt,name=total_alloc,json=totalAlloc,proto3" json:"total_alloc,omitempty"`
+	Sys           uint64                 `protobuf:"varint,5,opt,name=sys,proto3" json:"sys,omitempty"`
+	Mallocs       uint64                 `protobuf:"varint,6,opt,name=mallocs,proto3" json:"mallocs,omitempty"`
+	Frees         uint64                 `protobuf:"varint,7,opt,name=frees,proto3" json:"frees,omitempty"`
+	LiveObjects   uint64                 `protobuf:"varint,8,opt,name=live_objects,json=liveObjects,proto3" json:"live_objects,omitempty"`
+	PauseTotalNs  uint64                 `protobuf:"varint,9,opt,name=pause_total_ns,json=pauseTotalNs,proto3" json:"pause_total_ns,omitempty"`
+	Uptime        uint32                 `protobuf:"varint,10,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CoreRuntimeStats) Reset() {
+	*x = CoreRuntimeStats{}
+	mi := &file_proto_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CoreRuntimeStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoreRuntimeStats) ProtoMessage() {}
+
+func (x *CoreRuntimeStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoreRuntimeStats.ProtoReflect.Descriptor instead.
+func (*CoreRuntimeStats) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CoreRuntimeStats) GetNumGoroutine() uint32 {
+	if x != nil {
+		return x.NumGoroutine
+	}
+	return 0
+}
+
+func (x *CoreRuntimeStats) GetNumGc() uint32 {
+	if x != nil {
+		return x.NumGc
+	}
+	return 0
+}
+
+func (x *CoreRuntimeStats) GetAlloc() uint64 {
+	if x != nil {
+		return x.Alloc
+	}
+	return 0
+}
+
+func (x *CoreRuntimeStats) GetTotalAlloc() uint64 {
+	if x != nil {
+		return x.TotalAlloc
+	}
+	return 0
+}
+
+func (x *CoreRuntimeStats) GetSys() uint64 {
+	if x != nil {
+		return x.Sys
+	}
+	return 0
+}
+
+func (x *CoreRuntimeStats) GetMallocs() uint64 {
+	if x != nil {
+		return x.Mallocs
+	}
+	return 0
+}
+
+func (x *CoreRuntimeStats) GetFrees() uint64 {
+	if x != nil {
+		return x.Frees
+	}
+	return 0
+}
+
+func (x *CoreRuntimeStats) GetLiveObjects() uint64 {
+	if x != nil {
+		return x.LiveObjects
+	}
+	return 0
+}
+
+func (x *CoreRuntimeStats) GetPauseTotalNs() uint64 {
+	if x != nil {
+		return x.PauseTotalNs
+	}
+	return 0
+}
+
+func (x *CoreRuntimeStats) GetUptime() uint32 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
+
+type BackendStats struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Running        bool                   `protobuf:"varint,1,opt,name=running,proto3" json:"running,omitempty"`
+	Pid            *uint32                `protobuf:"varint,2,opt,name=pid,proto3,oneof" json:"pid,omitempty"`
+	Uptime         *uint64                `protobuf:"varint,3,opt,name=uptime,proto3,oneof" json:"uptime,omitempty"`
+	Restarts       uint64                 `protobuf:"varint,4,opt,name=restarts,proto3" json:"restarts,omitempty"`
+	LastExitReason *string                `protobuf:"bytes,5,opt,name=last_exit_reason,json=lastExitReason,proto3,oneof" json:"last_exit_reason,omitempty"`
+	Rss            *uint64                `protobuf:"varint,6,opt,name=rss,proto3,oneof" json:"rss,omitempty"`
+	CoreStats      *CoreRuntimeStats      `protobuf:"bytes,7,opt,name=core_stats,json=coreStats,proto3,oneof" json:"core_stats,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BackendStats) Reset() {
+	*x = BackendStats{}
+	mi := &file_proto_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackendStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackendStats) ProtoMessage() {}
+
+func (x *BackendStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackendStats.ProtoReflect.Descriptor instead.
+func (*BackendStats) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *BackendStats) GetRunning() bool {
+	if x != nil {
+		return x.Running
+	}
+	return false
+}
+
+func (x *BackendStats) GetPid() uint32 {
+	if x != nil && x.Pid != nil {
+		return *x.Pid
+	}
+	return 0
+}
+
+func (x *BackendStats) GetUptime() uint64 {
+	if x != nil && x.Uptime != nil {
+		return *x.Uptime
+	}
+	return 0
+}
+
+func (x *BackendStats) GetRestarts() uint64 {
+	if x != nil {
+		return x.Restarts
+	}
+	return 0
+}
+
+func (x *BackendStats) GetLastExitReason() string {
+	if x != nil && x.LastExitReason != nil {
+		return *x.LastExitReason
+	}
+	return ""
+}
+
+func (x *BackendStats) GetRss() uint64 {
+	if x != nil && x.Rss != nil {
+		return *x.Rss
+	}
+	return 0
+}
+
+func (x *BackendStats) GetCoreStats() *CoreRuntimeStats {
+	if x != nil {
+		return x.CoreStats
+	}
+	return nil
+}
+
+type UserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Uid           uint32                 `protobuf:"varint,1,opt,name=uid,proto3" json:"uid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserRequest) Reset() {
+	*x = UserRequest{}
+	mi := &file_proto_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserRequest) ProtoMessage() {}
+
+func (x *UserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserRequest.ProtoReflect.Descriptor instead.
+func (*UserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UserRequest) GetUid() uint32 {
+	if x != nil {
+		return x.Uid
+	}
+	return 0
+}
+
+type InboundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InboundRequest) Reset() {
+	*x = InboundRequest{}
+	mi := &file_proto_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InboundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InboundRequest) ProtoMessage() {}
+
+func (x *InboundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InboundRequest.ProtoReflect.Descriptor instead.
+func (*InboundRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *InboundRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+type InboundUsers struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Users         []*User                `protobuf:"bytes,2,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InboundUsers) Reset() {
+	*x = InboundUsers{}
+	mi := &file_proto_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InboundUsers) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InboundUsers) ProtoMessage() {}
+
+func (x *InboundUsers) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InboundUsers.ProtoReflect.Descriptor instead.
+func (*InboundUsers) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *InboundUsers) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *InboundUsers) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type InboundUsersCounts struct {
+	state         protoimpl.MessageState                  `protogen:"open.v1"`
+	Counts        []*InboundUsersCounts_InboundUsersCount `protobuf:"bytes,1,rep,name=counts,proto3" json:"counts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InboundUsersCounts) Reset() {
+	*x = InboundUsersCounts{}
+	mi := &file_proto_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InboundUsersCounts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InboundUsersCounts) ProtoMessage() {}
+
+func (x *InboundUsersCounts) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InboundUsersCounts.ProtoReflect.Descriptor instead.
+func (*InboundUsersCounts) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *InboundUsersCounts) GetCounts() []*InboundUsersCounts_InboundUsersCount {
+	if x != nil {
+		return x.Counts
+	}
+	return nil
+}
+
+type NetworkInterface struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	HardwareAddr  string                 `protobuf:"bytes,2,opt,name=hardware_addr,json=hardwareAddr,proto3" json:"hardware_addr,omitempty"`
+	Addresses     []string               `protobuf:"bytes,3,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Up            bool                   `protobuf:"varint,4,opt,name=up,proto3" json:"up,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NetworkInterface) Reset() {
+	*x = NetworkInterface{}
+	mi := &file_proto_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NetworkInterface) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkInterface) ProtoMessage() {}
+
+func (x *NetworkInterface) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkInterface.ProtoReflect.Descriptor instead.
+func (*NetworkInterface) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *NetworkInterface) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NetworkInterface) GetHardwareAddr() string {
+	if x != nil {
+		return x.HardwareAddr
+	}
+	return ""
+}
+
+func (x *NetworkInterface) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+func (x *NetworkInterface) GetUp() bool {
+	if x != nil {
+		return x.Up
+	}
+	return false
+}
+
+type CoreVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Backend       string                 `protobuf:"bytes,1,opt,name=backend,proto3" json:"backend,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CoreVersion) Reset() {
+	*x = CoreVersion{}
+	mi := &file_proto_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CoreVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoreVersion) ProtoMessage() {}
+
+func (x *CoreVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoreVersion.ProtoReflect.Descriptor instead.
+func (*CoreVersion) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CoreVersion) GetBackend() string {
+	if x != nil {
+		return x.Backend
+	}
+	return ""
+}
+
+func (x *CoreVersion) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CoreVersion) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+type NodeInfo struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Version         string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	Hostname        string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Kernel          string                 `protobuf:"bytes,3,opt,name=kernel,proto3" json:"kernel,omitempty"`
+	CpuCount        uint32                 `protobuf:"varint,4,opt,name=cpu_count,json=cpuCount,proto3" json:"cpu_count,omitempty"`
+	Load1           float64                `protobuf:"fixed64,5,opt,name=load1,proto3" json:"load1,omitempty"`
+	Load5           float64                `protobuf:"fixed64,6,opt,name=load5,proto3" json:"load5,omitempty"`
+	Load15          float64                `protobuf:"fixed64,7,opt,name=load15,proto3" json:"load15,omitempty"`
+	MemoryTotal     uint64                 `protobuf:"varint,8,opt,name=memory_total,json=memoryTotal,proto3" json:"memory_total,omitempty"`
+	MemoryAvailable uint64                 `protobuf:"varint,9,opt,name=memory_available,json=memoryAvailable,proto3" json:"memory_available,omitempty"`
+	Uptime          uint64                 `protobuf:"varint,10,opt,name=uptime,proto3" json:"uptime,omitempty"`
+	Interfaces      []*NetworkInterface    `protobuf:"bytes,11,rep,name=interfaces,proto3" json:"interfaces,omitempty"`
+	Cores           []*CoreVersion         `protobuf:"bytes,12,rep,name=cores,proto3" json:"cores,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *NodeInfo) Reset() {
+	*x = NodeInfo{}
+	mi := &file_proto_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeInfo) ProtoMessage() {}
+
+func (x *NodeInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeInfo.ProtoReflect.Descriptor instead.
+func (*NodeInfo) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *NodeInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *NodeInfo) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *NodeInfo) GetKernel() string {
+	if x != nil {
+		return x.Kernel
+	}
+	return ""
+}
+
+func (x *NodeInfo) GetCpuCount() uint32 {
+	if x != nil {
+		return x.CpuCount
+	}
+	return 0
+}
+
+func (x *NodeInfo) GetLoad1() float64 {
+	if x != nil {
+		return x.Load1
+	}
+	return 0
+}
+
+func (x *NodeInfo) GetLoad5() float64 {
+	if x != nil {
+		return x.Load5
+	}
+	return 0
+}
+
+func (x *NodeInfo) GetLoad15() float64 {
+	if x != nil {
+		return x.Load15
+	}
+	return 0
+}
+
+func (x *NodeInfo) GetMemoryTotal() uint64 {
+	if x != nil {
+		return x.MemoryTotal
+	}
+	return 0
+}
+
+func (x *NodeInfo) GetMemoryAvailable() uint64 {
+	if x != nil {
+		return x.MemoryAvailable
+	}
+	return 0
+}
+
+func (x *NodeInfo) GetUptime() uint64 {
+	if x != nil {
+		return x.Uptime
+	}
+	return 0
+}
+
+func (x *NodeInfo) GetInterfaces() []*NetworkInterface {
+	if x != nil {
+		return x.Interfaces
+	}
+	return nil
+}
+
+func (x *NodeInfo) GetCores() []*CoreVersion {
+	if x != nil {
+		return x.Cores
+	}
+	return nil
+}
+
+type ValidateBackendConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BackendName   string                 `protobuf:"bytes,1,opt,name=backend_name,json=backendName,proto3" json:"backend_name,omitempty"`
+	Config        *BackendConfig         `protobuf:"bytes,2,opt,name=config,proto3,oneof" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateBackendConfigRequest) Reset() {
+	*x = ValidateBackendConfigRequest{}
+	mi := &file_proto_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateBackendConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateBackendConfigRequest) ProtoMessage() {}
+
+func (x *ValidateBackendConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateBackendConfigRequest.ProtoReflect.Descriptor instead.
+func (*ValidateBackendConfigRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ValidateBackendConfigRequest) GetBackendName() string {
+	if x != nil {
+		return x.BackendName
+	}
+	return ""
+}
+
+func (x *ValidateBackendConfigRequest) GetConfig() *BackendConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type ConfigError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stage         string                 `protobuf:"bytes,1,opt,name=stage,proto3" json:"stage,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfigError) Reset() {
+	*x = ConfigError{}
+	mi := &file_proto_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfigError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfigError) ProtoMessage() {}
+
+func (x *ConfigError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfigError.ProtoReflect.Descriptor instead.
+func (*ConfigError) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ConfigError) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *ConfigError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ValidateBackendConfigResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	Errors        []*ConfigError         `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidateBackendConfigResponse) Reset() {
+	*x = ValidateBackendConfigResponse{}
+	mi := &file_proto_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateBackendConfigResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateBackendConfigResponse) ProtoMessage() {}
+
+func (x *ValidateBackendConfigResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateBackendConfigResponse.ProtoReflect.Descriptor instead.
+func (*ValidateBackendConfigResponse) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ValidateBackendConfigResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *ValidateBackendConfigResponse) GetErrors() []*ConfigError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type DiffBackendConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BackendName   string                 `protobuf:"bytes,1,opt,name=backend_name,json=backendName,proto3" json:"backend_name,omitempty"`
+	Config        *BackendConfig         `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiffBackendConfigRequest) Reset() {
+	*x = DiffBackendConfigRequest{}
+	mi := &file_proto_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiffBackendConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiffBackendConfigRequest) ProtoMessage() {}
+
+func (x *DiffBackendConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiffBackendConfigRequest.ProtoReflect.Descriptor instead.
+func (*DiffBackendConfigRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DiffBackendConfigRequest) GetBackendName() string {
+	if x != nil {
+		return x.BackendName
+	}
+	return ""
+}
+
+func (x *DiffBackendConfigRequest) GetConfig() *BackendConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type InboundDiff struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Protocol      string                 `protobuf:"bytes,2,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	OldPort       *uint32                `protobuf:"varint,3,opt,name=old_port,json=oldPort,proto3,oneof" json:"old_port,omitempty"`
+	NewPort       *uint32                `protobuf:"varint,4,opt,name=new_port,json=newPort,proto3,oneof" json:"new_port,omitempty"`
+	PortChanged   bool                   `protobuf:"varint,5,opt,name=port_changed,json=portChanged,proto3" json:"port_changed,omitempty"`
+	TlsChanged    bool                   `protobuf:"varint,6,opt,name=tls_changed,json=tlsChanged,proto3" json:"tls_changed,omitempty"`
+	ChangedFields []string               `protobuf:"bytes,7,rep,name=changed_fields,json=changedFields,proto3" json:"changed_fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InboundDiff) Reset() {
+	*x = InboundDiff{}
+	mi := &file_proto_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InboundDiff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InboundDiff) ProtoMessage() {}
+
+func (x *InboundDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InboundDiff.ProtoReflect.Descriptor instead.
+func (*InboundDiff) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *InboundDiff) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *InboundDiff) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *InboundDiff) GetOldPort() uint32 {
+	if x != nil && x.OldPort != nil {
+		return *x.OldPort
+	}
+	return 0
+}
+
+func (x *InboundDiff) GetNewPort() uint32 {
+	if x != nil && x.NewPort != nil {
+		return *x.NewPort
+	}
+	return 0
+}
+
+func (x *InboundDiff) GetPortChanged() bool {
+	if x != nil {
+		return x.PortChanged
+	}
+	return false
+}
+
+func (x *InboundDiff) GetTlsChanged() bool {
+	if x != nil {
+		return x.TlsChanged
+	}
+	return false
+}
+
+func (x *InboundDiff) GetChangedFields() []string {
+	if x != nil {
+		return x.ChangedFields
+	}
+	return nil
+}
+
+type BackendConfigDiff struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Added           []*InboundDiff         `protobuf:"bytes,1,rep,name=added,proto3" json:"added,omitempty"`
+	Removed         []*InboundDiff         `protobuf:"bytes,2,rep,name=removed,proto3" json:"removed,omitempty"`
+	Modified        []*InboundDiff         `protobuf:"bytes,3,rep,name=modified,proto3" json:"modified,omitempty"`
+	ChangedSections []string               `protobuf:"bytes,4,rep,name=changed_sections,json=changedSections,proto3" json:"changed_sections,omitempty"`
+	RestartRequired bool                   `protobuf:"varint,5,opt,name=restart_required,json=restartRequired,proto3" json:"restart_required,omitempty"`
+	ReloadRequired  bool                   `protobuf:"varint,6,opt,name=reload_required,json=reloadRequired,proto3" json:"reload_required,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *BackendConfigDiff) Reset() {
+	*x = BackendConfigDiff{}
+	mi := &file_proto_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BackendConfigDiff) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BackendConfigDiff) ProtoMessage() {}
+
+func (x *BackendConfigDiff) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BackendConfigDiff.ProtoReflect.Descriptor instead.
+func (*BackendConfigDiff) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *BackendConfigDiff) GetAdded() []*InboundDiff {
+	if x != nil {
+		return x.Added
+	}
+	return nil
+}
+
+func (x *BackendConfigDiff) GetRemoved() []*InboundDiff {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
+func (x *BackendConfigDiff) GetModified() []*InboundDiff {
+	if x != nil {
+		return x.Modified
+	}
+	return nil
+}
+
+func (x *BackendConfigDiff) GetChangedSections() []string {
+	if x != nil {
+		return x.ChangedSections
+	}
+	return nil
+}
+
+func (x *BackendConfigDiff) GetRestartRequired() bool {
+	if x != nil {
+		return x.RestartRequired
+	}
+	return false
+}
+
+func (x *BackendConfigDiff) GetReloadRequired() bool {
+	if x != nil {
+		return x.ReloadRequired
+	}
+	return false
+}
+
+type InboundConfigRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BackendName   string                 `protobuf:"bytes,1,opt,name=backend_name,json=backendName,proto3" json:"backend_name,omitempty"`
+	Config        string                 `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InboundConfigRequest) Reset() {
+	*x = InboundConfigRequest{}
+	mi := &file_proto_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InboundConfigRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InboundConfigRequest) ProtoMessage() {}
+
+func (x *InboundConfigRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InboundConfigRequest.ProtoReflect.Descriptor instead.
+func (*InboundConfigRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *InboundConfigRequest) GetBackendName() string {
+	if x != nil {
+		return x.BackendName
+	}
+	return ""
+}
+
+func (x *InboundConfigRequest) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+type RemoveInboundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BackendName   string                 `protobuf:"bytes,1,opt,name=backend_name,json=backendName,proto3" json:"backend_name,omitempty"`
+	Tag           string                 `protobuf:"bytes,2,opt,name=tag,proto3" json:"tag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveInboundRequest) Reset() {
+	*x = RemoveInboundRequest{}
+	mi := &file_proto_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveInboundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveInboundRequest) ProtoMessage() {}
+
+func (x *RemoveInboundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveInboundRequest.ProtoReflect.Descriptor instead.
+func (*RemoveInboundRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *RemoveInboundRequest) GetBackendName() string {
+	if x != nil {
+		return x.BackendName
+	}
+	return ""
+}
+
+func (x *RemoveInboundRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+type ListConnectionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BackendName   *string                `protobuf:"bytes,1,opt,name=backend_name,json=backendName,proto3,oneof" json:"backend_name,omitempty"`
+	Uid           *uint32                `protobuf:"varint,2,opt,name=uid,proto3,oneof" json:"uid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListConnectionsRequest) Reset() {
+	*x = ListConnectionsRequest{}
+	mi := &file_proto_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListConnectionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListConnectionsRequest) ProtoMessage() {}
+
+func (x *ListConnectionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListConnectionsRequest.ProtoReflect.Descriptor instead.
+func (*ListConnectionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListConnectionsRequest) GetBackendName() string {
+	if x != nil && x.BackendName != nil {
+		return *x.BackendName
+	}
+	return ""
+}
+
+func (x *ListConnectionsRequest) GetUid() uint32 {
+	if x != nil && x.Uid != nil {
+		return *x.Uid
+	}
+	return 0
+}
+
+type Connection struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	BackendName string                 `protobuf:"bytes,2,opt,name=backend_name,json=backendName,proto3" json:"backend_name,omitempty"`
+	InboundTag  string                 `protobuf:"bytes,3,opt,name=inbound_tag,json=inboundTag,proto3" json:"inbound_tag,omitempty"`
+	Network     string                 `protobuf:"bytes,4,opt,name=network,proto3" json:"network,omitempty"`
+	Source      string                 `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	Destination string                 `protobuf:"bytes,6,opt,name=destination,proto3" json:"destination,omitempty"`
+	Upload      uint64                 `protobuf:"varint,7,opt,name=upload,proto3" json:"upload,omitempty"`
+	Download    uint64                 `protobuf:"varint,8,opt,name=download,proto3" json:"download,omitempty"`
+	// started_at is a unix timestamp in seconds.
+	StartedAt     uint64 `protobuf:"varint,9,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Connection) Reset() {
+	*x = Connection{}
+	mi := &file_proto_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Connection) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Connection) ProtoMessage() {}
+
+func (x *Connection) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Connection.ProtoReflect.Descriptor instead.
+func (*Connection) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *Connection) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Connection) GetBackendName() string {
+	if x != nil {
+		return x.BackendName
+	}
+	return ""
+}
+
+func (x *Connection) GetInboundTag() string {
+	if x != nil {
+		return x.InboundTag
+	}
+	return ""
+}
+
+func (x *Connection) GetNetwork() string {
+	if x != nil {
+		return x.Network
+	}
+	return ""
+}
+
+func (x *Connection) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *Connection) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *Connection) GetUpload() uint64 {
+	if x != nil {
+		return x.Upload
+	}
+	return 0
+}
+
+func (x *Connection) GetDownload() uint64 {
+	if x != nil {
+		return x.Download
+	}
+	return 0
+}
+
+func (x *Connection) GetStartedAt() uint64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+type UserConnections struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user is the "id.username" identifier, empty for connections the core
+	// doesn't attribute to a user.
+	User          string        `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Uid           *uint32       `protobuf:"varint,2,opt,name=uid,proto3,oneof" json:"uid,omitempty"`
+	Connections   []*Connection `protobuf:"bytes,3,rep,name=connections,proto3" json:"connections,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UsersStats) Reset() {
-	*x = UsersStats{}
-	mi := &file_proto_service_proto_msgTypes[7]
+func (x *UserConnections) Reset() {
+	*x = UserConnections{}
+	mi := &file_proto_service_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UsersStats) String() string {
+func (x *UserConnections) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UsersStats) ProtoMessage() {}
+func (*UserConnections) ProtoMessage() {}
 
-func (x *UsersStats) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_service_proto_msgTypes[7]
+func (x *UserConnections) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -458,40 +2406,54 @@ func (x *UsersStats) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UsersStats.ProtoReflect.Descriptor instead.
-func (*UsersStats) Descriptor() ([]byte, []int) {
-	return file_proto_service_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use UserConnections.ProtoReflect.Descriptor instead.
+func (*UserConnections) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *UsersStats) GetUsersStats() []*UsersStats_UserStats {
+func (x *UserConnections) GetUser() string {
 	if x != nil {
-		return x.UsersStats
+		return x.User
+	}
+	return ""
+}
+
+func (x *UserConnections) GetUid() uint32 {
+	if x != nil && x.Uid != nil {
+		return *x.Uid
+	}
+	return 0
+}
+
+func (x *UserConnections) GetConnections() []*Connection {
+	if x != nil {
+		return x.Connections
 	}
 	return nil
 }
 
-type LogLine struct {
+type ConnectionsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Line          string                 `protobuf:"bytes,1,opt,name=line,proto3" json:"line,omitempty"`
+	Users         []*UserConnections     `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LogLine) Reset() {
-	*x = LogLine{}
-	mi := &file_proto_service_proto_msgTypes[8]
+func (x *ConnectionsResponse) Reset() {
+	*x = ConnectionsResponse{}
+	mi := &file_proto_service_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LogLine) String() string {
+func (x *ConnectionsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LogLine) ProtoMessage() {}
+func (*ConnectionsResponse) ProtoMessage() {}
 
-func (x *LogLine) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_service_proto_msgTypes[8]
+func (x *ConnectionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -502,41 +2464,40 @@ func (x *LogLine) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LogLine.ProtoReflect.Descriptor instead.
-func (*LogLine) Descriptor() ([]byte, []int) {
-	return file_proto_service_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use ConnectionsResponse.ProtoReflect.Descriptor instead.
+func (*ConnectionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{39}
 }
 
-func (x *LogLine) GetLine() string {
+func (x *ConnectionsResponse) GetUsers() []*UserConnections {
 	if x != nil {
-		return x.Line
+		return x.Users
 	}
-	return ""
+	return nil
 }
 
-type BackendConfig struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Configuration string                 `protobuf:"bytes,1,opt,name=configuration,proto3" json:"configuration,omitempty"`
-	ConfigFormat  ConfigFormat           `protobuf:"varint,2,opt,name=config_format,json=configFormat,proto3,enum=api.ConfigFormat" json:"config_format,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type DisconnectUserResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ClosedConnections uint32                 `protobuf:"varint,1,opt,name=closed_connections,json=closedConnections,proto3" json:"closed_connections,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
-func (x *BackendConfig) Reset() {
-	*x = BackendConfig{}
-	mi := &file_proto_service_proto_msgTypes[9]
+func (x *DisconnectUserResponse) Reset() {
+	*x = DisconnectUserResponse{}
+	mi := &file_proto_service_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BackendConfig) String() string {
+func (x *DisconnectUserResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BackendConfig) ProtoMessage() {}
+func (*DisconnectUserResponse) ProtoMessage() {}
 
-func (x *BackendConfig) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_service_proto_msgTypes[9]
+func (x *DisconnectUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -547,48 +2508,47 @@ func (x *BackendConfig) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BackendConfig.ProtoReflect.Descriptor instead.
-func (*BackendConfig) Descriptor() ([]byte, []int) {
-	return file_proto_service_proto_rawDescGZIP(), []int{9}
-}
-
-func (x *BackendConfig) GetConfiguration() string {
-	if x != nil {
-		return x.Configuration
-	}
-	return ""
+// Deprecated: Use DisconnectUserResponse.ProtoReflect.Descriptor instead.
+func (*DisconnectUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *BackendConfig) GetConfigFormat() ConfigFormat {
+func (x *DisconnectUserResponse) GetClosedConnections() uint32 {
 	if x != nil {
-		return x.ConfigFormat
+		return x.ClosedConnections
 	}
-	return ConfigFormat_PLAIN
+	return 0
 }
 
-type BackendLogsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	BackendName   string                 `protobuf:"bytes,1,opt,name=backend_name,json=backendName,proto3" json:"backend_name,omitempty"`
-	IncludeBuffer bool                   `protobuf:"varint,2,opt,name=include_buffer,json=includeBuffer,proto3" json:"include_buffer,omitempty"`
+// Times are unix timestamps in seconds.
+type AuditLogQuery struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Since       *uint64                `protobuf:"varint,1,opt,name=since,proto3,oneof" json:"since,omitempty"`
+	Until       *uint64                `protobuf:"varint,2,opt,name=until,proto3,oneof" json:"until,omitempty"`
+	Rpc         *string                `protobuf:"bytes,3,opt,name=rpc,proto3,oneof" json:"rpc,omitempty"`
+	Uid         *uint32                `protobuf:"varint,4,opt,name=uid,proto3,oneof" json:"uid,omitempty"`
+	BackendName *string                `protobuf:"bytes,5,opt,name=backend_name,json=backendName,proto3,oneof" json:"backend_name,omitempty"`
+	// limit keeps the most recent records, 0 returns all of them.
+	Limit         uint32 `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BackendLogsRequest) Reset() {
-	*x = BackendLogsRequest{}
-	mi := &file_proto_service_proto_msgTypes[10]
+func (x *AuditLogQuery) Reset() {
+	*x = AuditLogQuery{}
+	mi := &file_proto_service_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BackendLogsRequest) String() string {
+func (x *AuditLogQuery) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BackendLogsRequest) ProtoMessage() {}
+func (*AuditLogQuery) ProtoMessage() {}
 
-func (x *BackendLogsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_service_proto_msgTypes[10]
+func (x *AuditLogQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -599,48 +2559,89 @@ func (x *BackendLogsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BackendLogsRequest.ProtoReflect.Descriptor instead.
-func (*BackendLogsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_service_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use AuditLogQuery.ProtoReflect.Descriptor instead.
+func (*AuditLogQuery) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *BackendLogsRequest) GetBackendName() string {
-	if x != nil {
-		return x.BackendName
+func (x *AuditLogQuery) GetSince() uint64 {
+	if x != nil && x.Since != nil {
+		return *x.Since
+	}
+	return 0
+}
+
+func (x *AuditLogQuery) GetUntil() uint64 {
+	if x != nil && x.Until != nil {
+		return *x.Until
+	}
+	return 0
+}
+
+func (x *AuditLogQuery) GetRpc() string {
+	if x != nil && x.Rpc != nil {
+		return *x.Rpc
 	}
 	return ""
 }
 
-func (x *BackendLogsRequest) GetIncludeBuffer() bool {
+func (x *AuditLogQuery) GetUid() uint32 {
+	if x != nil && x.Uid != nil {
+		return *x.Uid
+	}
+	return 0
+}
+
+func (x *AuditLogQuery) GetBackendName() string {
+	if x != nil && x.BackendName != nil {
+		return *x.BackendName
+	}
+	return ""
+}
+
+func (x *AuditLogQuery) GetLimit() uint32 {
 	if x != nil {
-		return x.IncludeBuffer
+		return x.Limit
 	}
-	return false
+	return 0
 }
 
-type RestartBackendRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	BackendName   string                 `protobuf:"bytes,1,opt,name=backend_name,json=backendName,proto3" json:"backend_name,omitempty"`
-	Config        *BackendConfig         `protobuf:"bytes,2,opt,name=config,proto3,oneof" json:"config,omitempty"`
+type AuditRecord struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Time  uint64                 `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	// caller is the subject of the client certificate, or the peer address
+	// without one.
+	Caller    string   `protobuf:"bytes,2,opt,name=caller,proto3" json:"caller,omitempty"`
+	Peer      string   `protobuf:"bytes,3,opt,name=peer,proto3" json:"peer,omitempty"`
+	Rpc       string   `protobuf:"bytes,4,opt,name=rpc,proto3" json:"rpc,omitempty"`
+	RequestId string   `protobuf:"bytes,5,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Users     []uint32 `protobuf:"varint,6,rep,packed,name=users,proto3" json:"users,omitempty"`
+	Backends  []string `protobuf:"bytes,7,rep,name=backends,proto3" json:"backends,omitempty"`
+	Inbounds  []string `protobuf:"bytes,8,rep,name=inbounds,proto3" json:"inbounds,omitempty"`
+	// outcome is the gRPC status code of the call.
+	Outcome string  `protobuf:"bytes,9,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	Error   *string `protobuf:"bytes,10,opt,name=error,proto3,oneof" json:"error,omitempty"`
+	// usage_cursor is the cursor acknowledged by the call, if any.
+	UsageCursor   *string `protobuf:"bytes,11,opt,name=usage_cursor,json=usageCursor,proto3,oneof" json:"usage_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RestartBackendRequest) Reset() {
-	*x = RestartBackendRequest{}
-	mi := &file_proto_service_proto_msgTypes[11]
+func (x *AuditRecord) Reset() {
+	*x = AuditRecord{}
+	mi := &file_proto_service_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RestartBackendRequest) String() string {
+func (x *AuditRecord) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RestartBackendRequest) ProtoMessage() {}
+func (*AuditRecord) ProtoMessage() {}
 
-func (x *RestartBackendRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_service_proto_msgTypes[11]
+func (x *AuditRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -651,47 +2652,110 @@ func (x *RestartBackendRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RestartBackendRequest.ProtoReflect.Descriptor instead.
-func (*RestartBackendRequest) Descriptor() ([]byte, []int) {
-	return file_proto_service_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use AuditRecord.ProtoReflect.Descriptor instead.
+func (*AuditRecord) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{42}
 }
 
-func (x *RestartBackendRequest) GetBackendName() string {
+func (x *AuditRecord) GetTime() uint64 {
 	if x != nil {
-		return x.BackendName
+		return x.Time
+	}
+	return 0
+}
+
+func (x *AuditRecord) GetCaller() string {
+	if x != nil {
+		return x.Caller
 	}
 	return ""
 }
 
-func (x *RestartBackendRequest) GetConfig() *BackendConfig {
+func (x *AuditRecord) GetPeer() string {
 	if x != nil {
-		return x.Config
+		return x.Peer
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetRpc() string {
+	if x != nil {
+		return x.Rpc
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetUsers() []uint32 {
+	if x != nil {
+		return x.Users
 	}
 	return nil
 }
 
-type BackendStats struct {
+func (x *AuditRecord) GetBackends() []string {
+	if x != nil {
+		return x.Backends
+	}
+	return nil
+}
+
+func (x *AuditRecord) GetInbounds() []string {
+	if x != nil {
+		return x.Inbounds
+	}
+	return nil
+}
+
+func (x *AuditRecord) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetUsageCursor() string {
+	if x != nil && x.UsageCursor != nil {
+		return *x.UsageCursor
+	}
+	return ""
+}
+
+type AuditRecords struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Running       bool                   `protobuf:"varint,1,opt,name=running,proto3" json:"running,omitempty"`
+	Records       []*AuditRecord         `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BackendStats) Reset() {
-	*x = BackendStats{}
-	mi := &file_proto_service_proto_msgTypes[12]
+func (x *AuditRecords) Reset() {
+	*x = AuditRecords{}
+	mi := &file_proto_service_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BackendStats) String() string {
+func (x *AuditRecords) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BackendStats) ProtoMessage() {}
+func (*AuditRecords) ProtoMessage() {}
 
-func (x *BackendStats) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_service_proto_msgTypes[12]
+func (x *AuditRecords) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -702,16 +2766,16 @@ func (x *BackendStats) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BackendStats.ProtoReflect.Descriptor instead.
-func (*BackendStats) Descriptor() ([]byte, []int) {
-	return file_proto_service_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use AuditRecords.ProtoReflect.Descriptor instead.
+func (*AuditRecords) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{43}
 }
 
-func (x *BackendStats) GetRunning() bool {
+func (x *AuditRecords) GetRecords() []*AuditRecord {
 	if x != nil {
-		return x.Running
+		return x.Records
 	}
-	return false
+	return nil
 }
 
 type UsersStats_UserStats struct {
@@ -724,7 +2788,7 @@ type UsersStats_UserStats struct {
 
 func (x *UsersStats_UserStats) Reset() {
 	*x = UsersStats_UserStats{}
-	mi := &file_proto_service_proto_msgTypes[13]
+	mi := &file_proto_service_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -736,7 +2800,7 @@ func (x *UsersStats_UserStats) String() string {
 func (*UsersStats_UserStats) ProtoMessage() {}
 
 func (x *UsersStats_UserStats) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_service_proto_msgTypes[13]
+	mi := &file_proto_service_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -749,7 +2813,7 @@ func (x *UsersStats_UserStats) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UsersStats_UserStats.ProtoReflect.Descriptor instead.
 func (*UsersStats_UserStats) Descriptor() ([]byte, []int) {
-	return file_proto_service_proto_rawDescGZIP(), []int{7, 0}
+	return file_proto_service_proto_rawDescGZIP(), []int{11, 0}
 }
 
 func (x *UsersStats_UserStats) GetUid() uint32 {
@@ -766,6 +2830,58 @@ func (x *UsersStats_UserStats) GetUsage() uint64 {
 	return 0
 }
 
+type InboundUsersCounts_InboundUsersCount struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Users         uint32                 `protobuf:"varint,2,opt,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InboundUsersCounts_InboundUsersCount) Reset() {
+	*x = InboundUsersCounts_InboundUsersCount{}
+	mi := &file_proto_service_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InboundUsersCounts_InboundUsersCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InboundUsersCounts_InboundUsersCount) ProtoMessage() {}
+
+func (x *InboundUsersCounts_InboundUsersCount) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_service_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InboundUsersCounts_InboundUsersCount.ProtoReflect.Descriptor instead.
+func (*InboundUsersCounts_InboundUsersCount) Descriptor() ([]byte, []int) {
+	return file_proto_service_proto_rawDescGZIP(), []int{24, 0}
+}
+
+func (x *InboundUsersCounts_InboundUsersCount) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *InboundUsersCounts_InboundUsersCount) GetUsers() uint32 {
+	if x != nil {
+		return x.Users
+	}
+	return 0
+}
+
 var File_proto_service_proto protoreflect.FileDescriptor
 
 const file_proto_service_proto_rawDesc = "" +
@@ -795,14 +2911,43 @@ const file_proto_service_proto_rawDesc = "" +
 	"\binbounds\x18\x02 \x03(\v2\f.api.InboundR\binbounds\"9\n" +
 	"\tUsersData\x12,\n" +
 	"\n" +
-	"users_data\x18\x01 \x03(\v2\r.api.UserDataR\tusersData\"}\n" +
+	"users_data\x18\x01 \x03(\v2\r.api.UserDataR\tusersData\"3\n" +
+	"\tUserError\x12\x10\n" +
+	"\x03uid\x18\x01 \x01(\rR\x03uid\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\";\n" +
+	"\x11SyncUsersResponse\x12&\n" +
+	"\x06errors\x18\x01 \x03(\v2\x0e.api.UserErrorR\x06errors\"\x8b\x01\n" +
+	"\x17RepopulateUsersResponse\x12\x14\n" +
+	"\x05added\x18\x01 \x01(\rR\x05added\x12\x18\n" +
+	"\aremoved\x18\x02 \x01(\rR\aremoved\x12\x18\n" +
+	"\achanged\x18\x03 \x01(\rR\achanged\x12&\n" +
+	"\x06errors\x18\x04 \x03(\v2\x0e.api.UserErrorR\x06errors\"\x8e\x01\n" +
+	"\vUserTraffic\x12\x10\n" +
+	"\x03uid\x18\x01 \x01(\rR\x03uid\x12\x18\n" +
+	"\abackend\x18\x02 \x01(\tR\abackend\x12\x1f\n" +
+	"\vinbound_tag\x18\x03 \x01(\tR\n" +
+	"inboundTag\x12\x16\n" +
+	"\x06uplink\x18\x04 \x01(\x04R\x06uplink\x12\x1a\n" +
+	"\bdownlink\x18\x05 \x01(\x04R\bdownlink\"\xcc\x01\n" +
 	"\n" +
 	"UsersStats\x12:\n" +
 	"\vusers_stats\x18\x01 \x03(\v2\x19.api.UsersStats.UserStatsR\n" +
-	"usersStats\x1a3\n" +
+	"usersStats\x125\n" +
+	"\rusers_traffic\x18\x02 \x03(\v2\x10.api.UserTrafficR\fusersTraffic\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\x1a3\n" +
 	"\tUserStats\x12\x10\n" +
 	"\x03uid\x18\x01 \x01(\rR\x03uid\x12\x14\n" +
-	"\x05usage\x18\x02 \x01(\x04R\x05usage\"\x1d\n" +
+	"\x05usage\x18\x02 \x01(\x04R\x05usage\"M\n" +
+	"\x18CollectUsersStatsRequest\x12\"\n" +
+	"\n" +
+	"ack_cursor\x18\x01 \x01(\tH\x00R\tackCursor\x88\x01\x01B\r\n" +
+	"\v_ack_cursor\"\x80\x01\n" +
+	"\x17StreamUsersStatsRequest\x12)\n" +
+	"\x10interval_seconds\x18\x01 \x01(\rR\x0fintervalSeconds\x12(\n" +
+	"\rresume_cursor\x18\x02 \x01(\tH\x00R\fresumeCursor\x88\x01\x01B\x10\n" +
+	"\x0e_resume_cursor\".\n" +
+	"\x14AckUsersStatsRequest\x12\x16\n" +
+	"\x06cursor\x18\x01 \x01(\tR\x06cursor\"\x1d\n" +
 	"\aLogLine\x12\x12\n" +
 	"\x04line\x18\x01 \x01(\tR\x04line\"m\n" +
 	"\rBackendConfig\x12$\n" +
@@ -814,27 +2959,202 @@ const file_proto_service_proto_rawDesc = "" +
 	"\x15RestartBackendRequest\x12!\n" +
 	"\fbackend_name\x18\x01 \x01(\tR\vbackendName\x12/\n" +
 	"\x06config\x18\x02 \x01(\v2\x12.api.BackendConfigH\x00R\x06config\x88\x01\x01B\t\n" +
-	"\a_config\"(\n" +
+	"\a_config\"\xa8\x02\n" +
+	"\x10CoreRuntimeStats\x12#\n" +
+	"\rnum_goroutine\x18\x01 \x01(\rR\fnumGoroutine\x12\x15\n" +
+	"\x06num_gc\x18\x02 \x01(\rR\x05numGc\x12\x14\n" +
+	"\x05alloc\x18\x03 \x01(\x04R\x05alloc\x12\x1f\n" +
+	"\vtotal_alloc\x18\x04 \x01(\x04R\n" +
+	"totalAlloc\x12\x10\n" +
+	"\x03sys\x18\x05 \x01(\x04R\x03sys\x12\x18\n" +
+	"\amallocs\x18\x06 \x01(\x04R\amallocs\x12\x14\n" +
+	"\x05frees\x18\a \x01(\x04R\x05frees\x12!\n" +
+	"\flive_objects\x18\b \x01(\x04R\vliveObjects\x12$\n" +
+	"\x0epause_total_ns\x18\t \x01(\x04R\fpauseTotalNs\x12\x16\n" +
+	"\x06uptime\x18\n" +
+	" \x01(\rR\x06uptime\"\xb8\x02\n" +
 	"\fBackendStats\x12\x18\n" +
-	"\arunning\x18\x01 \x01(\bR\arunning*-\n" +
+	"\arunning\x18\x01 \x01(\bR\arunning\x12\x15\n" +
+	"\x03pid\x18\x02 \x01(\rH\x00R\x03pid\x88\x01\x01\x12\x1b\n" +
+	"\x06uptime\x18\x03 \x01(\x04H\x01R\x06uptime\x88\x01\x01\x12\x1a\n" +
+	"\brestarts\x18\x04 \x01(\x04R\brestarts\x12-\n" +
+	"\x10last_exit_reason\x18\x05 \x01(\tH\x02R\x0elastExitReason\x88\x01\x01\x12\x15\n" +
+	"\x03rss\x18\x06 \x01(\x04H\x03R\x03rss\x88\x01\x01\x129\n" +
+	"\n" +
+	"core_stats\x18\a \x01(\v2\x15.api.CoreRuntimeStatsH\x04R\tcoreStats\x88\x01\x01B\x06\n" +
+	"\x04_pidB\t\n" +
+	"\a_uptimeB\x13\n" +
+	"\x11_last_exit_reasonB\x06\n" +
+	"\x04_rssB\r\n" +
+	"\v_core_stats\"\x1f\n" +
+	"\vUserRequest\x12\x10\n" +
+	"\x03uid\x18\x01 \x01(\rR\x03uid\"\"\n" +
+	"\x0eInboundRequest\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\"A\n" +
+	"\fInboundUsers\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x1f\n" +
+	"\x05users\x18\x02 \x03(\v2\t.api.UserR\x05users\"\x94\x01\n" +
+	"\x12InboundUsersCounts\x12A\n" +
+	"\x06counts\x18\x01 \x03(\v2).api.InboundUsersCounts.InboundUsersCountR\x06counts\x1a;\n" +
+	"\x11InboundUsersCount\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x14\n" +
+	"\x05users\x18\x02 \x01(\rR\x05users\"y\n" +
+	"\x10NetworkInterface\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12#\n" +
+	"\rhardware_addr\x18\x02 \x01(\tR\fhardwareAddr\x12\x1c\n" +
+	"\taddresses\x18\x03 \x03(\tR\taddresses\x12\x0e\n" +
+	"\x02up\x18\x04 \x01(\bR\x02up\"U\n" +
+	"\vCoreVersion\x12\x18\n" +
+	"\abackend\x18\x01 \x01(\tR\abackend\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\"\xfe\x02\n" +
+	"\bNodeInfo\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x1a\n" +
+	"\bhostname\x18\x02 \x01(\tR\bhostname\x12\x16\n" +
+	"\x06kernel\x18\x03 \x01(\tR\x06kernel\x12\x1b\n" +
+	"\tcpu_count\x18\x04 \x01(\rR\bcpuCount\x12\x14\n" +
+	"\x05load1\x18\x05 \x01(\x01R\x05load1\x12\x14\n" +
+	"\x05load5\x18\x06 \x01(\x01R\x05load5\x12\x16\n" +
+	"\x06load15\x18\a \x01(\x01R\x06load15\x12!\n" +
+	"\fmemory_total\x18\b \x01(\x04R\vmemoryTotal\x12)\n" +
+	"\x10memory_available\x18\t \x01(\x04R\x0fmemoryAvailable\x12\x16\n" +
+	"\x06uptime\x18\n" +
+	" \x01(\x04R\x06uptime\x125\n" +
+	"\n" +
+	"interfaces\x18\v \x03(\v2\x15.api.NetworkInterfaceR\n" +
+	"interfaces\x12&\n" +
+	"\x05cores\x18\f \x03(\v2\x10.api.CoreVersionR\x05cores\"}\n" +
+	"\x1cValidateBackendConfigRequest\x12!\n" +
+	"\fbackend_name\x18\x01 \x01(\tR\vbackendName\x12/\n" +
+	"\x06config\x18\x02 \x01(\v2\x12.api.BackendConfigH\x00R\x06config\x88\x01\x01B\t\n" +
+	"\a_config\"=\n" +
+	"\vConfigError\x12\x14\n" +
+	"\x05stage\x18\x01 \x01(\tR\x05stage\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"_\n" +
+	"\x1dValidateBackendConfigResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12(\n" +
+	"\x06errors\x18\x02 \x03(\v2\x10.api.ConfigErrorR\x06errors\"i\n" +
+	"\x18DiffBackendConfigRequest\x12!\n" +
+	"\fbackend_name\x18\x01 \x01(\tR\vbackendName\x12*\n" +
+	"\x06config\x18\x02 \x01(\v2\x12.api.BackendConfigR\x06config\"\x80\x02\n" +
+	"\vInboundDiff\x12\x10\n" +
+	"\x03tag\x18\x01 \x01(\tR\x03tag\x12\x1a\n" +
+	"\bprotocol\x18\x02 \x01(\tR\bprotocol\x12\x1e\n" +
+	"\bold_port\x18\x03 \x01(\rH\x00R\aoldPort\x88\x01\x01\x12\x1e\n" +
+	"\bnew_port\x18\x04 \x01(\rH\x01R\anewPort\x88\x01\x01\x12!\n" +
+	"\fport_changed\x18\x05 \x01(\bR\vportChanged\x12\x1f\n" +
+	"\vtls_changed\x18\x06 \x01(\bR\n" +
+	"tlsChanged\x12%\n" +
+	"\x0echanged_fields\x18\a \x03(\tR\rchangedFieldsB\v\n" +
+	"\t_old_portB\v\n" +
+	"\t_new_port\"\x94\x02\n" +
+	"\x11BackendConfigDiff\x12&\n" +
+	"\x05added\x18\x01 \x03(\v2\x10.api.InboundDiffR\x05added\x12*\n" +
+	"\aremoved\x18\x02 \x03(\v2\x10.api.InboundDiffR\aremoved\x12,\n" +
+	"\bmodified\x18\x03 \x03(\v2\x10.api.InboundDiffR\bmodified\x12)\n" +
+	"\x10changed_sections\x18\x04 \x03(\tR\x0fchangedSections\x12)\n" +
+	"\x10restart_required\x18\x05 \x01(\bR\x0frestartRequired\x12'\n" +
+	"\x0freload_required\x18\x06 \x01(\bR\x0ereloadRequired\"Q\n" +
+	"\x14InboundConfigRequest\x12!\n" +
+	"\fbackend_name\x18\x01 \x01(\tR\vbackendName\x12\x16\n" +
+	"\x06config\x18\x02 \x01(\tR\x06config\"K\n" +
+	"\x14RemoveInboundRequest\x12!\n" +
+	"\fbackend_name\x18\x01 \x01(\tR\vbackendName\x12\x10\n" +
+	"\x03tag\x18\x02 \x01(\tR\x03tag\"p\n" +
+	"\x16ListConnectionsRequest\x12&\n" +
+	"\fbackend_name\x18\x01 \x01(\tH\x00R\vbackendName\x88\x01\x01\x12\x15\n" +
+	"\x03uid\x18\x02 \x01(\rH\x01R\x03uid\x88\x01\x01B\x0f\n" +
+	"\r_backend_nameB\x06\n" +
+	"\x04_uid\"\x87\x02\n" +
+	"\n" +
+	"Connection\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fbackend_name\x18\x02 \x01(\tR\vbackendName\x12\x1f\n" +
+	"\vinbound_tag\x18\x03 \x01(\tR\n" +
+	"inboundTag\x12\x18\n" +
+	"\anetwork\x18\x04 \x01(\tR\anetwork\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\x12 \n" +
+	"\vdestination\x18\x06 \x01(\tR\vdestination\x12\x16\n" +
+	"\x06upload\x18\a \x01(\x04R\x06upload\x12\x1a\n" +
+	"\bdownload\x18\b \x01(\x04R\bdownload\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\t \x01(\x04R\tstartedAt\"w\n" +
+	"\x0fUserConnections\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12\x15\n" +
+	"\x03uid\x18\x02 \x01(\rH\x00R\x03uid\x88\x01\x01\x121\n" +
+	"\vconnections\x18\x03 \x03(\v2\x0f.api.ConnectionR\vconnectionsB\x06\n" +
+	"\x04_uid\"A\n" +
+	"\x13ConnectionsResponse\x12*\n" +
+	"\x05users\x18\x01 \x03(\v2\x14.api.UserConnectionsR\x05users\"G\n" +
+	"\x16DisconnectUserResponse\x12-\n" +
+	"\x12closed_connections\x18\x01 \x01(\rR\x11closedConnections\"\xe6\x01\n" +
+	"\rAuditLogQuery\x12\x19\n" +
+	"\x05since\x18\x01 \x01(\x04H\x00R\x05since\x88\x01\x01\x12\x19\n" +
+	"\x05until\x18\x02 \x01(\x04H\x01R\x05until\x88\x01\x01\x12\x15\n" +
+	"\x03rpc\x18\x03 \x01(\tH\x02R\x03rpc\x88\x01\x01\x12\x15\n" +
+	"\x03uid\x18\x04 \x01(\rH\x03R\x03uid\x88\x01\x01\x12&\n" +
+	"\fbackend_name\x18\x05 \x01(\tH\x04R\vbackendName\x88\x01\x01\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\rR\x05limitB\b\n" +
+	"\x06_sinceB\b\n" +
+	"\x06_untilB\x06\n" +
+	"\x04_rpcB\x06\n" +
+	"\x04_uidB\x0f\n" +
+	"\r_backend_name\"\xc4\x02\n" +
+	"\vAuditRecord\x12\x12\n" +
+	"\x04time\x18\x01 \x01(\x04R\x04time\x12\x16\n" +
+	"\x06caller\x18\x02 \x01(\tR\x06caller\x12\x12\n" +
+	"\x04peer\x18\x03 \x01(\tR\x04peer\x12\x10\n" +
+	"\x03rpc\x18\x04 \x01(\tR\x03rpc\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x05 \x01(\tR\trequestId\x12\x14\n" +
+	"\x05users\x18\x06 \x03(\rR\x05users\x12\x1a\n" +
+	"\bbackends\x18\a \x03(\tR\bbackends\x12\x1a\n" +
+	"\binbounds\x18\b \x03(\tR\binbounds\x12\x18\n" +
+	"\aoutcome\x18\t \x01(\tR\aoutcome\x12\x19\n" +
+	"\x05error\x18\n" +
+	" \x01(\tH\x00R\x05error\x88\x01\x01\x12&\n" +
+	"\fusage_cursor\x18\v \x01(\tH\x01R\vusageCursor\x88\x01\x01B\b\n" +
+	"\x06_errorB\x0f\n" +
+	"\r_usage_cursor\":\n" +
+	"\fAuditRecords\x12*\n" +
+	"\arecords\x18\x01 \x03(\v2\x10.api.AuditRecordR\arecords*-\n" +
 	"\fConfigFormat\x12\t\n" +
 	"\x05PLAIN\x10\x00\x12\b\n" +
 	"\x04JSON\x10\x01\x12\b\n" +
-	"\x04YAML\x10\x022\xae\x03\n" +
-	"\vMarzService\x12(\n" +
-	"\tSyncUsers\x12\r.api.UserData\x1a\n" +
-	".api.Empty(\x01\x12-\n" +
-	"\x0fRepopulateUsers\x12\x0e.api.UsersData\x1a\n" +
-	".api.Empty\x122\n" +
+	"\x04YAML\x10\x022\xf3\n" +
+	"\n" +
+	"\vMarzService\x124\n" +
+	"\tSyncUsers\x12\r.api.UserData\x1a\x16.api.SyncUsersResponse(\x01\x12?\n" +
+	"\x0fRepopulateUsers\x12\x0e.api.UsersData\x1a\x1c.api.RepopulateUsersResponse\x122\n" +
 	"\rFetchBackends\x12\n" +
 	".api.Empty\x1a\x15.api.BackendsResponse\x12.\n" +
 	"\x0fFetchUsersStats\x12\n" +
-	".api.Empty\x1a\x0f.api.UsersStats\x126\n" +
+	".api.Empty\x1a\x0f.api.UsersStats\x12C\n" +
+	"\x11CollectUsersStats\x12\x1d.api.CollectUsersStatsRequest\x1a\x0f.api.UsersStats\x12C\n" +
+	"\x10StreamUsersStats\x12\x1c.api.StreamUsersStatsRequest\x1a\x0f.api.UsersStats0\x01\x126\n" +
+	"\rAckUsersStats\x12\x19.api.AckUsersStatsRequest\x1a\n" +
+	".api.Empty\x126\n" +
 	"\x12FetchBackendConfig\x12\f.api.Backend\x1a\x12.api.BackendConfig\x128\n" +
 	"\x0eRestartBackend\x12\x1a.api.RestartBackendRequest\x1a\n" +
 	".api.Empty\x12<\n" +
 	"\x11StreamBackendLogs\x12\x17.api.BackendLogsRequest\x1a\f.api.LogLine0\x01\x122\n" +
-	"\x0fGetBackendStats\x12\f.api.Backend\x1a\x11.api.BackendStatsB\rZ\vgrpc/api/pbb\x06proto3"
+	"\x0fGetBackendStats\x12\f.api.Backend\x1a\x11.api.BackendStats\x12*\n" +
+	"\aGetUser\x12\x10.api.UserRequest\x1a\r.api.UserData\x12:\n" +
+	"\x10ListInboundUsers\x12\x13.api.InboundRequest\x1a\x11.api.InboundUsers\x128\n" +
+	"\x11CountInboundUsers\x12\n" +
+	".api.Empty\x1a\x17.api.InboundUsersCounts\x12(\n" +
+	"\vGetNodeInfo\x12\n" +
+	".api.Empty\x1a\r.api.NodeInfo\x12^\n" +
+	"\x15ValidateBackendConfig\x12!.api.ValidateBackendConfigRequest\x1a\".api.ValidateBackendConfigResponse\x12J\n" +
+	"\x11DiffBackendConfig\x12\x1d.api.DiffBackendConfigRequest\x1a\x16.api.BackendConfigDiff\x125\n" +
+	"\n" +
+	"AddInbound\x12\x19.api.InboundConfigRequest\x1a\f.api.Inbound\x129\n" +
+	"\x0eReplaceInbound\x12\x19.api.InboundConfigRequest\x1a\f.api.Inbound\x126\n" +
+	"\rRemoveInbound\x12\x19.api.RemoveInboundRequest\x1a\n" +
+	".api.Empty\x12H\n" +
+	"\x0fListConnections\x12\x1b.api.ListConnectionsRequest\x1a\x18.api.ConnectionsResponse\x12?\n" +
+	"\x0eDisconnectUser\x12\x10.api.UserRequest\x1a\x1b.api.DisconnectUserResponse\x126\n" +
+	"\rQueryAuditLog\x12\x12.api.AuditLogQuery\x1a\x11.api.AuditRecordsB\rZ\vgrpc/api/pbb\x06proto3"
 
 var (
 	file_proto_service_proto_rawDescOnce sync.Once
@@ -849,23 +3169,55 @@ func file_proto_service_proto_rawDescGZIP() []byte {
 }
 
 var file_proto_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_service_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_proto_service_proto_msgTypes = make([]protoimpl.MessageInfo, 46)
 var file_proto_service_proto_goTypes = []any{
-	(ConfigFormat)(0),             // 0: api.ConfigFormat
-	(*Empty)(nil),                 // 1: api.Empty
-	(*Backend)(nil),               // 2: api.Backend
-	(*BackendsResponse)(nil),      // 3: api.BackendsResponse
-	(*Inbound)(nil),               // 4: api.Inbound
-	(*User)(nil),                  // 5: api.User
-	(*UserData)(nil),              // 6: api.UserData
-	(*UsersData)(nil),             // 7: api.UsersData
-	(*UsersStats)(nil),            // 8: api.UsersStats
-	(*LogLine)(nil),               // 9: api.LogLine
-	(*BackendConfig)(nil),         // 10: api.BackendConfig
-	(*BackendLogsRequest)(nil),    // 11: api.BackendLogsRequest
-	(*RestartBackendRequest)(nil), // 12: api.RestartBackendRequest
-	(*BackendStats)(nil),          // 13: api.BackendStats
-	(*UsersStats_UserStats)(nil),  // 14: api.UsersStats.UserStats
+	(ConfigFormat)(0),                            // 0: api.ConfigFormat
+	(*Empty)(nil),                                // 1: api.Empty
+	(*Backend)(nil),                              // 2: api.Backend
+	(*BackendsResponse)(nil),                     // 3: api.BackendsResponse
+	(*Inbound)(nil),                              // 4: api.Inbound
+	(*User)(nil),                                 // 5: api.User
+	(*UserData)(nil),                             // 6: api.UserData
+	(*UsersData)(nil),                            // 7: api.UsersData
+	(*UserError)(nil),                            // 8: api.UserError
+	(*SyncUsersResponse)(nil),                    // 9: api.SyncUsersResponse
+	(*RepopulateUsersResponse)(nil),              // 10: api.RepopulateUsersResponse
+	(*UserTraffic)(nil),                          // 11: api.UserTraffic
+	(*UsersStats)(nil),                           // 12: api.UsersStats
+	(*CollectUsersStatsRequest)(nil),             // 13: api.CollectUsersStatsRequest
+	(*StreamUsersStatsRequest)(nil),              // 14: api.StreamUsersStatsRequest
+	(*AckUsersStatsRequest)(nil),                 // 15: api.AckUsersStatsRequest
+	(*LogLine)(nil),                              // 16: api.LogLine
+	(*BackendConfig)(nil),                        // 17: api.BackendConfig
+	(*BackendLogsRequest)(nil),                   // 18: api.BackendLogsRequest
+	(*RestartBackendRequest)(nil),                // 19: api.RestartBackendRequest
+	(*CoreRuntimeStats)(nil),                     // 20: api.CoreRuntimeStats
+	(*BackendStats)(nil),                         // 21: api.BackendStats
+	(*UserRequest)(nil),                          // 22: api.UserRequest
+	(*InboundRequest)(nil),                       // 23: api.InboundRequest
+	(*InboundUsers)(nil),                         // 24: api.InboundUsers
+	(*InboundUsersCounts)(nil),                   // 25: api.InboundUsersCounts
+	(*NetworkInterface)(nil),                     // 26: api.NetworkInterface
+	(*CoreVersion)(nil),                          // 27: api.CoreVersion
+	(*NodeInfo)(nil),                             // 28: api.NodeInfo
+	(*ValidateBackendConfigRequest)(nil),         // 29: api.ValidateBackendConfigRequest
+	(*ConfigError)(nil),                          // 30: api.ConfigError
+	(*ValidateBackendConfigResponse)(nil),        // 31: api.ValidateBackendConfigResponse
+	(*DiffBackendConfigRequest)(nil),             // 32: api.DiffBackendConfigRequest
+	(*InboundDiff)(nil),                          // 33: api.InboundDiff
+	(*BackendConfigDiff)(nil),                    // 34: api.BackendConfigDiff
+	(*InboundConfigRequest)(nil),                 // 35: api.InboundConfigRequest
+	(*RemoveInboundRequest)(nil),                 // 36: api.RemoveInboundRequest
+	(*ListConnectionsRequest)(nil),               // 37: api.ListConnectionsRequest
+	(*Connection)(nil),                           // 38: api.Connection
+	(*UserConnections)(nil),                      // 39: api.UserConnections
+	(*ConnectionsResponse)(nil),                  // 40: api.ConnectionsResponse
+	(*DisconnectUserResponse)(nil),               // 41: api.DisconnectUserResponse
+	(*AuditLogQuery)(nil),                        // 42: api.AuditLogQuery
+	(*AuditRecord)(nil),                          // 43: api.AuditRecord
+	(*AuditRecords)(nil),                         // 44: api.AuditRecords
+	(*UsersStats_UserStats)(nil),                 // 45: api.UsersStats.UserStats
+	(*InboundUsersCounts_InboundUsersCount)(nil), // 46: api.InboundUsersCounts.InboundUsersCount
 }
 var file_proto_service_proto_depIdxs = []int32{
 	4,  // 0: api.Backend.inbounds:type_name -> api.Inbound
@@ -873,30 +3225,77 @@ var file_proto_service_proto_depIdxs = []int32{
 	5,  // 2: api.UserData.user:type_name -> api.User
 	4,  // 3: api.UserData.inbounds:type_name -> api.Inbound
 	6,  // 4: api.UsersData.users_data:type_name -> api.UserData
-	14, // 5: api.UsersStats.users_stats:type_name -> api.UsersStats.UserStats
-	0,  // 6: api.BackendConfig.config_format:type_name -> api.ConfigFormat
-	10, // 7: api.RestartBackendRequest.config:type_name -> api.BackendConfig
-	6,  // 8: api.MarzService.SyncUsers:input_type -> api.UserData
-	7,  // 9: api.MarzService.RepopulateUsers:input_type -> api.UsersData
-	1,  // 10: api.MarzService.FetchBackends:input_type -> api.Empty
-	1,  // 11: api.MarzService.FetchUsersStats:input_type -> api.Empty
-	2,  // 12: api.MarzService.FetchBackendConfig:input_type -> api.Backend
-	12, // 13: api.MarzService.RestartBackend:input_type -> api.RestartBackendRequest
-	11, // 14: api.MarzService.StreamBackendLogs:input_type -> api.BackendLogsRequest
-	2,  // 15: api.MarzService.GetBackendStats:input_type -> api.Backend
-	1,  // 16: api.MarzService.SyncUsers:output_type -> api.Empty
-	1,  // 17: api.MarzService.RepopulateUsers:output_type -> api.Empty
-	3,  // 18: api.MarzService.FetchBackends:output_type -> api.BackendsResponse
-	8,  // 19: api.MarzService.FetchUsersStats:output_type -> api.UsersStats
-	10, // 20: api.MarzService.FetchBackendConfig:output_type -> api.BackendConfig
-	1,  // 21: api.MarzService.RestartBackend:output_type -> api.Empty
-	9,  // 22: api.MarzService.StreamBackendLogs:output_type -> api.LogLine
-	13, // 23: api.MarzService.GetBackendStats:output_type -> api.BackendStats
-	16, // [16:24] is the sub-list for method output_type
-	8,  // [8:16] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	8,  // 5: api.SyncUsersResponse.errors:type_name -> api.UserError
+	8,  // 6: api.RepopulateUsersResponse.errors:type_name -> api.UserError
+	45, // 7: api.UsersStats.users_stats:type_name -> api.UsersStats.UserStats
+	11, // 8: api.UsersStats.users_traffic:type_name -> api.UserTraffic
+	0,  // 9: api.BackendConfig.config_format:type_name -> api.ConfigFormat
+	17, // 10: api.RestartBackendRequest.config:type_name -> api.BackendConfig
+	20, // 11: api.BackendStats.core_stats:type_name -> api.CoreRuntimeStats
+	5,  // 12: api.InboundUsers.users:type_name -> api.User
+	46, // 13: api.InboundUsersCounts.counts:type_name -> api.InboundUsersCounts.InboundUsersCount
+	26, // 14: api.NodeInfo.interfaces:type_name -> api.NetworkInterface
+	27, // 15: api.NodeInfo.cores:type_name -> api.CoreVersion
+	17, // 16: api.ValidateBackendConfigRequest.config:type_name -> api.BackendConfig
+	30, // 17: api.ValidateBackendConfigResponse.errors:type_name -> api.ConfigError
+	17, // 18: api.DiffBackendConfigRequest.config:type_name -> api.BackendConfig
+	33, // 19: api.BackendConfigDiff.added:type_name -> api.InboundDiff
+	33, // 20: api.BackendConfigDiff.removed:type_name -> api.InboundDiff
+	33, // 21: api.BackendConfigDiff.modified:type_name -> api.InboundDiff
+	38, // 22: api.UserConnections.connections:type_name -> api.Connection
+	39, // 23: api.ConnectionsResponse.users:type_name -> api.UserConnections
+	43, // 24: api.AuditRecords.records:type_name -> api.AuditRecord
+	6,  // 25: api.MarzService.SyncUsers:input_type -> api.UserData
+	7,  // 26: api.MarzService.RepopulateUsers:input_type -> api.UsersData
+	1,  // 27: api.MarzService.FetchBackends:input_type -> api.Empty
+	1,  // 28: api.MarzService.FetchUsersStats:input_type -> api.Empty
+	13, // 29: api.MarzService.CollectUsersStats:input_type -> api.CollectUsersStatsRequest
+	14, // 30: api.MarzService.StreamUsersStats:input_type -> api.StreamUsersStatsRequest
+	15, // 31: api.MarzService.AckUsersStats:input_type -> api.AckUsersStatsRequest
+	2,  // 32: api.MarzService.FetchBackendConfig:input_type -> api.Backend
+	19, // 33: api.MarzService.RestartBackend:input_type -> api.RestartBackendRequest
+	18, // 34: api.MarzService.StreamBackendLogs:input_type -> api.BackendLogsRequest
+	2,  // 35: api.MarzService.GetBackendStats:input_type -> api.Backend
+	22, // 36: api.MarzService.GetUser:input_type -> api.UserRequest
+	23, // 37: api.MarzService.ListInboundUsers:input_type -> api.InboundRequest
+	1,  // 38: api.MarzService.CountInboundUsers:input_type -> api.Empty
+	1,  // 39: api.MarzService.GetNodeInfo:input_type -> api.Empty
+	29, // 40: api.MarzService.ValidateBackendConfig:input_type -> api.ValidateBackendConfigRequest
+	32, // 41: api.MarzService.DiffBackendConfig:input_type -> api.DiffBackendConfigRequest
+	35, // 42: api.MarzService.AddInbound:input_type -> api.InboundConfigRequest
+	35, // 43: api.MarzService.ReplaceInbound:input_type -> api.InboundConfigRequest
+	36, // 44: api.MarzService.RemoveInbound:input_type -> api.RemoveInboundRequest
+	37, // 45: api.MarzService.ListConnections:input_type -> api.ListConnectionsRequest
+	22, // 46: api.MarzService.DisconnectUser:input_type -> api.UserRequest
+	42, // 47: api.MarzService.QueryAuditLog:input_type -> api.AuditLogQuery
+	9,  // 48: api.MarzService.SyncUsers:output_type -> api.SyncUsersResponse
+	10, // 49: api.MarzService.RepopulateUsers:output_type -> api.RepopulateUsersResponse
+	3,  // 50: api.MarzService.FetchBackends:output_type -> api.BackendsResponse
+	12, // 51: api.MarzService.FetchUsersStats:output_type -> api.UsersStats
+	12, // 52: api.MarzService.CollectUsersStats:output_type -> api.UsersStats
+	12, // 53: api.MarzService.StreamUsersStats:output_type -> api.UsersStats
+	1,  // 54: api.MarzService.AckUsersStats:output_type -> api.Empty
+	17, // 55: api.MarzService.FetchBackendConfig:output_type -> api.BackendConfig
+	1,  // 56: api.MarzService.RestartBackend:output_type -> api.Empty
+	16, // 57: api.MarzService.StreamBackendLogs:output_type -> api.LogLine
+	21, // 58: api.MarzService.GetBackendStats:output_type -> api.BackendStats
+	6,  // 59: api.MarzService.GetUser:output_type -> api.UserData
+	24, // 60: api.MarzService.ListInboundUsers:output_type -> api.InboundUsers
+	25, // 61: api.MarzService.CountInboundUsers:output_type -> api.InboundUsersCounts
+	28, // 62: api.MarzService.GetNodeInfo:output_type -> api.NodeInfo
+	31, // 63: api.MarzService.ValidateBackendConfig:output_type -> api.ValidateBackendConfigResponse
+	34, // 64: api.MarzService.DiffBackendConfig:output_type -> api.BackendConfigDiff
+	4,  // 65: api.MarzService.AddInbound:output_type -> api.Inbound
+	4,  // 66: api.MarzService.ReplaceInbound:output_type -> api.Inbound
+	1,  // 67: api.MarzService.RemoveInbound:output_type -> api.Empty
+	40, // 68: api.MarzService.ListConnections:output_type -> api.ConnectionsResponse
+	41, // 69: api.MarzService.DisconnectUser:output_type -> api.DisconnectUserResponse
+	44, // 70: api.MarzService.QueryAuditLog:output_type -> api.AuditRecords
+	48, // [48:71] is the sub-list for method output_type
+	25, // [25:48] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_proto_service_proto_init() }
@@ -906,14 +3305,23 @@ func file_proto_service_proto_init() {
 	}
 	file_proto_service_proto_msgTypes[1].OneofWrappers = []any{}
 	file_proto_service_proto_msgTypes[3].OneofWrappers = []any{}
-	file_proto_service_proto_msgTypes[11].OneofWrappers = []any{}
+	file_proto_service_proto_msgTypes[12].OneofWrappers = []any{}
+	file_proto_service_proto_msgTypes[13].OneofWrappers = []any{}
+	file_proto_service_proto_msgTypes[18].OneofWrappers = []any{}
+	file_proto_service_proto_msgTypes[20].OneofWrappers = []any{}
+	file_proto_service_proto_msgTypes[28].OneofWrappers = []any{}
+	file_proto_service_proto_msgTypes[32].OneofWrappers = []any{}
+	file_proto_service_proto_msgTypes[36].OneofWrappers = []any{}
+	file_proto_service_proto_msgTypes[38].OneofWrappers = []any{}
+	file_proto_service_proto_msgTypes[41].OneofWrappers = []any{}
+	file_proto_service_proto_msgTypes[42].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_service_proto_rawDesc), len(file_proto_service_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   14,
+			NumMessages:   46,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,28 +19,58 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	MarzService_SyncUsers_FullMethodName          = "/api.MarzService/SyncUsers"
-	MarzService_RepopulateUsers_FullMethodName    = "/api.MarzService/RepopulateUsers"
-	MarzService_FetchBackends_FullMethodName      = "/api.MarzService/FetchBackends"
-	MarzService_FetchUsersStats_FullMethodName    = "/api.MarzService/FetchUsersStats"
-	MarzService_FetchBackendConfig_FullMethodName = "/api.MarzService/FetchBackendConfig"
-	MarzService_RestartBackend_FullMethodName     = "/api.MarzService/RestartBackend"
-	MarzService_StreamBackendLogs_FullMethodName  = "/api.MarzService/StreamBackendLogs"
-	MarzService_GetBackendStats_FullMethodName    = "/api.MarzService/GetBackendStats"
+	MarzService_SyncUsers_FullMethodName             = "/api.MarzService/SyncUsers"
+	MarzService_RepopulateUsers_FullMethodName       = "/api.MarzService/RepopulateUsers"
+	MarzService_FetchBackends_FullMethodName         = "/api.MarzService/FetchBackends"
+	MarzService_FetchUsersStats_FullMethodName       = "/api.MarzService/FetchUsersStats"
+	MarzService_CollectUsersStats_FullMethodName     = "/api.MarzService/CollectUsersStats"
+	MarzService_StreamUsersStats_FullMethodName      = "/api.MarzService/StreamUsersStats"
+	MarzService_AckUsersStats_FullMethodName         = "/api.MarzService/AckUsersStats"
+	MarzService_FetchBackendConfig_FullMethodName    = "/api.MarzService/FetchBackendConfig"
+	MarzService_RestartBackend_FullMethodName        = "/api.MarzService/RestartBackend"
+	MarzService_StreamBackendLogs_FullMethodName     = "/api.MarzService/StreamBackendLogs"
+	MarzService_GetBackendStats_FullMethodName       = "/api.MarzService/GetBackendStats"
+	MarzService_GetUser_FullMethodName               = "/api.MarzService/GetUser"
+	MarzService_ListInboundUsers_FullMethodName      = "/api.MarzService/ListInboundUsers"
+	MarzService_CountInboundUsers_FullMethodName     = "/api.MarzService/CountInboundUsers"
+	MarzService_GetNodeInfo_FullMethodName           = "/api.MarzService/GetNodeInfo"
+	MarzService_ValidateBackendConfig_FullMethodName = "/api.MarzService/ValidateBackendConfig"
+	MarzService_DiffBackendConfig_FullMethodName     = "/api.MarzService/DiffBackendConfig"
+	MarzService_AddInbound_FullMethodName            = "/api.MarzService/AddInbound"
+	MarzService_ReplaceInbound_FullMethodName        = "/api.MarzService/ReplaceInbound"
+	MarzService_RemoveInbound_FullMethodName         = "/api.MarzService/RemoveInbound"
+	MarzService_ListConnections_FullMethodName       = "/api.MarzService/ListConnections"
+	MarzService_DisconnectUser_FullMethodName        = "/api.MarzService/DisconnectUser"
+	MarzService_QueryAuditLog_FullMethodName         = "/api.MarzService/QueryAuditLog"
 )
 
 // MarzServiceClient is the client API for MarzService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type MarzServiceClient interface {
-	SyncUsers(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UserData, Empty], error)
-	RepopulateUsers(ctx context.Context, in *UsersData, opts ...grpc.CallOption) (*Empty, error)
+	SyncUsers(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UserData, SyncUsersResponse], error)
+	RepopulateUsers(ctx context.Context, in *UsersData, opts ...grpc.CallOption) (*RepopulateUsersResponse, error)
 	FetchBackends(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*BackendsResponse, error)
 	FetchUsersStats(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UsersStats, error)
+	CollectUsersStats(ctx context.Context, in *CollectUsersStatsRequest, opts ...grpc.CallOption) (*UsersStats, error)
+	StreamUsersStats(ctx context.Context, in *StreamUsersStatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UsersStats], error)
+	AckUsersStats(ctx context.Context, in *AckUsersStatsRequest, opts ...grpc.CallOption) (*Empty, error)
 	FetchBackendConfig(ctx context.Context, in *Backend, opts ...grpc.CallOption) (*BackendConfig, error)
 	RestartBackend(ctx context.Context, in *RestartBackendRequest, opts ...grpc.CallOption) (*Empty, error)
 	StreamBackendLogs(ctx context.Context, in *BackendLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogLine], error)
 	GetBackendStats(ctx context.Context, in *Backend, opts ...grpc.CallOption) (*BackendStats, error)
+	GetUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UserData, error)
+	ListInboundUsers(ctx context.Context, in *InboundRequest, opts ...grpc.CallOption) (*InboundUsers, error)
+	CountInboundUsers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*InboundUsersCounts, error)
+	GetNodeInfo(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NodeInfo, error)
+	ValidateBackendConfig(ctx context.Context, in *ValidateBackendConfigRequest, opts ...grpc.CallOption) (*ValidateBackendConfigResponse, error)
+	DiffBackendConfig(ctx context.Context, in *DiffBackendConfigRequest, opts ...grpc.CallOption) (*BackendConfigDiff, error)
+	AddInbound(ctx context.Context, in *InboundConfigRequest, opts ...grpc.CallOption) (*Inbound, error)
+	ReplaceInbound(ctx context.Context, in *InboundConfigRequest, opts ...grpc.CallOption) (*Inbound, error)
+	RemoveInbound(ctx context.Context, in *RemoveInboundRequest, opts ...grpc.CallOption) (*Empty, error)
+	ListConnections(ctx context.Context, in *ListConnectionsRequest, opts ...grpc.CallOption) (*ConnectionsResponse, error)
+	DisconnectUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*DisconnectUserResponse, error)
+	QueryAuditLog(ctx context.Context, in *AuditLogQuery, opts ...grpc.CallOption) (*AuditRecords, error)
 }
 
 type marzServiceClient struct {
@@ -51,22 +81,22 @@ func NewMarzServiceClient(cc grpc.ClientConnInterface) MarzServiceClient {
 	return &marzServiceClient{cc}
 }
 
-func (c *marzServiceClient) SyncUsers(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UserData, Empty], error) {
+func (c *marzServiceClient) SyncUsers(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UserData, SyncUsersResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &MarzService_ServiceDesc.Streams[0], MarzService_SyncUsers_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &grpc.GenericClientStream[UserData, Empty]{ClientStream: stream}
+	x := &grpc.GenericClientStream[UserData, SyncUsersResponse]{ClientStream: stream}
 	return x, nil
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type MarzService_SyncUsersClient = grpc.ClientStreamingClient[UserData, Empty]
+type MarzService_SyncUsersClient = grpc.ClientStreamingClient[UserData, SyncUsersResponse]
 
-func (c *marzServiceClient) RepopulateUsers(ctx context.Context, in *UsersData, opts ...grpc.CallOption) (*Empty, error) {
+func (c *marzServiceClient) RepopulateUsers(ctx context.Context, in *UsersData, opts ...grpc.CallOption) (*RepopulateUsersResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Empty)
+	out := new(RepopulateUsersResponse)
 	err := c.cc.Invoke(ctx, MarzService_RepopulateUsers_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
@@ -94,6 +124,45 @@ func (c *marzServiceClient) FetchUsersStats(ctx context.Context, in *Empty, opts
 	return out, nil
 }
 
+func (c *marzServiceClient) CollectUsersStats(ctx context.Context, in *CollectUsersStatsRequest, opts ...grpc.CallOption) (*UsersStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UsersStats)
+	err := c.cc.Invoke(ctx, MarzService_CollectUsersStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marzServiceClient) StreamUsersStats(ctx context.Context, in *StreamUsersStatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[UsersStats], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MarzService_ServiceDesc.Streams[1], MarzService_StreamUsersStats_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamUsersStatsRequest, UsersStats]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarzService_StreamUsersStatsClient = grpc.ServerStreamingClient[UsersStats]
+
+func (c *marzServiceClient) AckUsersStats(ctx context.Context, in *AckUsersStatsRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, MarzService_AckUsersStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *marzServiceClient) FetchBackendConfig(ctx context.Context, in *Backend, opts ...grpc.CallOption) (*BackendConfig, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(BackendConfig)
@@ -116,7 +185,7 @@ func (c *marzServiceClient) RestartBackend(ctx context.Context, in *RestartBacke
 
 func (c *marzServiceClient) StreamBackendLogs(ctx context.Context, in *BackendLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogLine], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	stream, err := c.cc.NewStream(ctx, &MarzService_ServiceDesc.Streams[1], MarzService_StreamBackendLogs_FullMethodName, cOpts...)
+	stream, err := c.cc.NewStream(ctx, &MarzService_ServiceDesc.Streams[2], MarzService_StreamBackendLogs_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -143,18 +212,153 @@ func (c *marzServiceClient) GetBackendStats(ctx context.Context, in *Backend, op
 	return out, nil
 }
 
+func (c *marzServiceClient) GetUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UserData, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserData)
+	err := c.cc.Invoke(ctx, MarzService_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marzServiceClient) ListInboundUsers(ctx context.Context, in *InboundRequest, opts ...grpc.CallOption) (*InboundUsers, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InboundUsers)
+	err := c.cc.Invoke(ctx, MarzService_ListInboundUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marzServiceClient) CountInboundUsers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*InboundUsersCounts, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InboundUsersCounts)
+	err := c.cc.Invoke(ctx, MarzService_CountInboundUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marzServiceClient) GetNodeInfo(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*NodeInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeInfo)
+	err := c.cc.Invoke(ctx, MarzService_GetNodeInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marzServiceClient) ValidateBackendConfig(ctx context.Context, in *ValidateBackendConfigRequest, opts ...grpc.CallOption) (*ValidateBackendConfigResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateBackendConfigResponse)
+	err := c.cc.Invoke(ctx, MarzService_ValidateBackendConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marzServiceClient) DiffBackendConfig(ctx context.Context, in *DiffBackendConfigRequest, opts ...grpc.CallOption) (*BackendConfigDiff, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BackendConfigDiff)
+	err := c.cc.Invoke(ctx, MarzService_DiffBackendConfig_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marzServiceClient) AddInbound(ctx context.Context, in *InboundConfigRequest, opts ...grpc.CallOption) (*Inbound, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Inbound)
+	err := c.cc.Invoke(ctx, MarzService_AddInbound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marzServiceClient) ReplaceInbound(ctx context.Context, in *InboundConfigRequest, opts ...grpc.CallOption) (*Inbound, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Inbound)
+	err := c.cc.Invoke(ctx, MarzService_ReplaceInbound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marzServiceClient) RemoveInbound(ctx context.Context, in *RemoveInboundRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, MarzService_RemoveInbound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marzServiceClient) ListConnections(ctx context.Context, in *ListConnectionsRequest, opts ...grpc.CallOption) (*ConnectionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConnectionsResponse)
+	err := c.cc.Invoke(ctx, MarzService_ListConnections_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marzServiceClient) DisconnectUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*DisconnectUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisconnectUserResponse)
+	err := c.cc.Invoke(ctx, MarzService_DisconnectUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *marzServiceClient) QueryAuditLog(ctx context.Context, in *AuditLogQuery, opts ...grpc.CallOption) (*AuditRecords, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuditRecords)
+	err := c.cc.Invoke(ctx, MarzService_QueryAuditLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MarzServiceServer is the server API for MarzService service.
 // All implementations must embed UnimplementedMarzServiceServer
 // for forward compatibility.
 type MarzServiceServer interface {
-	SyncUsers(grpc.ClientStreamingServer[UserData, Empty]) error
-	RepopulateUsers(context.Context, *UsersData) (*Empty, error)
+	SyncUsers(grpc.ClientStreamingServer[UserData, SyncUsersResponse]) error
+	RepopulateUsers(context.Context, *UsersData) (*RepopulateUsersResponse, error)
 	FetchBackends(context.Context, *Empty) (*BackendsResponse, error)
 	FetchUsersStats(context.Context, *Empty) (*UsersStats, error)
+	CollectUsersStats(context.Context, *CollectUsersStatsRequest) (*UsersStats, error)
+	StreamUsersStats(*StreamUsersStatsRequest, grpc.ServerStreamingServer[UsersStats]) error
+	AckUsersStats(context.Context, *AckUsersStatsRequest) (*Empty, error)
 	FetchBackendConfig(context.Context, *Backend) (*BackendConfig, error)
 	RestartBackend(context.Context, *RestartBackendRequest) (*Empty, error)
 	StreamBackendLogs(*BackendLogsRequest, grpc.ServerStreamingServer[LogLine]) error
 	GetBackendStats(context.Context, *Backend) (*BackendStats, error)
+	GetUser(context.Context, *UserRequest) (*UserData, error)
+	ListInboundUsers(context.Context, *InboundRequest) (*InboundUsers, error)
+	CountInboundUsers(context.Context, *Empty) (*InboundUsersCounts, error)
+	GetNodeInfo(context.Context, *Empty) (*NodeInfo, error)
+	ValidateBackendConfig(context.Context, *ValidateBackendConfigRequest) (*ValidateBackendConfigResponse, error)
+	DiffBackendConfig(context.Context, *DiffBackendConfigRequest) (*BackendConfigDiff, error)
+	AddInbound(context.Context, *InboundConfigRequest) (*Inbound, error)
+	ReplaceInbound(context.Context, *InboundConfigRequest) (*Inbound, error)
+	RemoveInbound(context.Context, *RemoveInboundRequest) (*Empty, error)
+	ListConnections(context.Context, *ListConnectionsRequest) (*ConnectionsResponse, error)
+	DisconnectUser(context.Context, *UserRequest) (*DisconnectUserResponse, error)
+	QueryAuditLog(context.Context, *AuditLogQuery) (*AuditRecords, error)
 	mustEmbedUnimplementedMarzServiceServer()
 }
 
@@ -165,10 +369,10 @@ type MarzServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedMarzServiceServer struct{}
 
-func (UnimplementedMarzServiceServer) SyncUsers(grpc.ClientStreamingServer[UserData, Empty]) error {
+func (UnimplementedMarzServiceServer) SyncUsers(grpc.ClientStreamingServer[UserData, SyncUsersResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method SyncUsers not implemented")
 }
-func (UnimplementedMarzServiceServer) RepopulateUsers(context.Context, *UsersData) (*Empty, error) {
+func (UnimplementedMarzServiceServer) RepopulateUsers(context.Context, *UsersData) (*RepopulateUsersResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RepopulateUsers not implemented")
 }
 func (UnimplementedMarzServiceServer) FetchBackends(context.Context, *Empty) (*BackendsResponse, error) {
@@ -177,6 +381,15 @@ func (UnimplementedMarzServiceServer) FetchBackends(context.Context, *Empty) (*B
 func (UnimplementedMarzServiceServer) FetchUsersStats(context.Context, *Empty) (*UsersStats, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FetchUsersStats not implemented")
 }
+func (UnimplementedMarzServiceServer) CollectUsersStats(context.Context, *CollectUsersStatsRequest) (*UsersStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CollectUsersStats not implemented")
+}
+func (UnimplementedMarzServiceServer) StreamUsersStats(*StreamUsersStatsRequest, grpc.ServerStreamingServer[UsersStats]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamUsersStats not implemented")
+}
+func (UnimplementedMarzServiceServer) AckUsersStats(context.Context, *AckUsersStatsRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AckUsersStats not implemented")
+}
 func (UnimplementedMarzServiceServer) FetchBackendConfig(context.Context, *Backend) (*BackendConfig, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method FetchBackendConfig not implemented")
 }
@@ -189,6 +402,42 @@ func (UnimplementedMarzServiceServer) StreamBackendLogs(*BackendLogsRequest, grp
 func (UnimplementedMarzServiceServer) GetBackendStats(context.Context, *Backend) (*BackendStats, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBackendStats not implemented")
 }
+func (UnimplementedMarzServiceServer) GetUser(context.Context, *UserRequest) (*UserData, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedMarzServiceServer) ListInboundUsers(context.Context, *InboundRequest) (*InboundUsers, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListInboundUsers not implemented")
+}
+func (UnimplementedMarzServiceServer) CountInboundUsers(context.Context, *Empty) (*InboundUsersCounts, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountInboundUsers not implemented")
+}
+func (UnimplementedMarzServiceServer) GetNodeInfo(context.Context, *Empty) (*NodeInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNodeInfo not implemented")
+}
+func (UnimplementedMarzServiceServer) ValidateBackendConfig(context.Context, *ValidateBackendConfigRequest) (*ValidateBackendConfigResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateBackendConfig not implemented")
+}
+func (UnimplementedMarzServiceServer) DiffBackendConfig(context.Context, *DiffBackendConfigRequest) (*BackendConfigDiff, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffBackendConfig not implemented")
+}
+func (UnimplementedMarzServiceServer) AddInbound(context.Context, *InboundConfigRequest) (*Inbound, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddInbound not implemented")
+}
+func (UnimplementedMarzServiceServer) ReplaceInbound(context.Context, *InboundConfigRequest) (*Inbound, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplaceInbound not implemented")
+}
+func (UnimplementedMarzServiceServer) RemoveInbound(context.Context, *RemoveInboundRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveInbound not implemented")
+}
+func (UnimplementedMarzServiceServer) ListConnections(context.Context, *ListConnectionsRequest) (*ConnectionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListConnections not implemented")
+}
+func (UnimplementedMarzServiceServer) DisconnectUser(context.Context, *UserRequest) (*DisconnectUserResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisconnectUser not implemented")
+}
+func (UnimplementedMarzServiceServer) QueryAuditLog(context.Context, *AuditLogQuery) (*AuditRecords, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryAuditLog not implemented")
+}
 func (UnimplementedMarzServiceServer) mustEmbedUnimplementedMarzServiceServer() {}
 func (UnimplementedMarzServiceServer) testEmbeddedByValue()                     {}
 
@@ -211,11 +460,11 @@ func RegisterMarzServiceServer(s grpc.ServiceRegistrar, srv MarzServiceServer) {
 }
 
 func _MarzService_SyncUsers_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(MarzServiceServer).SyncUsers(&grpc.GenericServerStream[UserData, Empty]{ServerStream: stream})
+	return srv.(MarzServiceServer).SyncUsers(&grpc.GenericServerStream[UserData, SyncUsersResponse]{ServerStream: stream})
 }
 
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
-type MarzService_SyncUsersServer = grpc.ClientStreamingServer[UserData, Empty]
+type MarzService_SyncUsersServer = grpc.ClientStreamingServer[UserData, SyncUsersResponse]
 
 func _MarzService_RepopulateUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UsersData)
@@ -271,6 +520,53 @@ func _MarzService_FetchUsersStats_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MarzService_CollectUsersStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CollectUsersStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarzServiceServer).CollectUsersStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarzService_CollectUsersStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarzServiceServer).CollectUsersStats(ctx, req.(*CollectUsersStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarzService_StreamUsersStats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamUsersStatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MarzServiceServer).StreamUsersStats(m, &grpc.GenericServerStream[StreamUsersStatsRequest, UsersStats]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MarzService_StreamUsersStatsServer = grpc.ServerStreamingServer[UsersStats]
+
+func _MarzService_AckUsersStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckUsersStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarzServiceServer).AckUsersStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarzService_AckUsersStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarzServiceServer).AckUsersStats(ctx, req.(*AckUsersStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _MarzService_FetchBackendConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Backend)
 	if err := dec(in); err != nil {
@@ -336,6 +632,222 @@ func _MarzService_GetBackendStats_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MarzService_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarzServiceServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarzService_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarzServiceServer).GetUser(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarzService_ListInboundUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InboundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarzServiceServer).ListInboundUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarzService_ListInboundUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarzServiceServer).ListInboundUsers(ctx, req.(*InboundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarzService_CountInboundUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarzServiceServer).CountInboundUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarzService_CountInboundUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarzServiceServer).CountInboundUsers(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarzService_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarzServiceServer).GetNodeInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarzService_GetNodeInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarzServiceServer).GetNodeInfo(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarzService_ValidateBackendConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateBackendConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarzServiceServer).ValidateBackendConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarzService_ValidateBackendConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarzServiceServer).ValidateBackendConfig(ctx, req.(*ValidateBackendConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarzService_DiffBackendConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiffBackendConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarzServiceServer).DiffBackendConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarzService_DiffBackendConfig_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarzServiceServer).DiffBackendConfig(ctx, req.(*DiffBackendConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarzService_AddInbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InboundConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarzServiceServer).AddInbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarzService_AddInbound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarzServiceServer).AddInbound(ctx, req.(*InboundConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarzService_ReplaceInbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InboundConfigRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarzServiceServer).ReplaceInbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarzService_ReplaceInbound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarzServiceServer).ReplaceInbound(ctx, req.(*InboundConfigRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarzService_RemoveInbound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveInboundRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarzServiceServer).RemoveInbound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarzService_RemoveInbound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarzServiceServer).RemoveInbound(ctx, req.(*RemoveInboundRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarzService_ListConnections_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListConnectionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarzServiceServer).ListConnections(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarzService_ListConnections_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarzServiceServer).ListConnections(ctx, req.(*ListConnectionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarzService_DisconnectUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarzServiceServer).DisconnectUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarzService_DisconnectUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarzServiceServer).DisconnectUser(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MarzService_QueryAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditLogQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MarzServiceServer).QueryAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MarzService_QueryAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MarzServiceServer).QueryAuditLog(ctx, req.(*AuditLogQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MarzService_ServiceDesc is the grpc.ServiceDesc for MarzService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -355,6 +867,14 @@ var MarzService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "FetchUsersStats",
 			Handler:    _MarzService_FetchUsersStats_Handler,
 		},
+		{
+			MethodName: "CollectUsersStats",
+			Handler:    _MarzService_CollectUsersStats_Handler,
+		},
+		{
+			MethodName: "AckUsersStats",
+			Handler:    _MarzService_AckUsersStats_Handler,
+		},
 		{
 			MethodName: "FetchBackendConfig",
 			Handler:    _MarzService_FetchBackendConfig_Handler,
@@ -367,6 +887,54 @@ var MarzService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetBackendStats",
 			Handler:    _MarzService_GetBackendStats_Handler,
 		},
+		{
+			MethodName: "GetUser",
+			Handler:    _MarzService_GetUser_Handler,
+		},
+		{
+			MethodName: "ListInboundUsers",
+			Handler:    _MarzService_ListInboundUsers_Handler,
+		},
+		{
+			MethodName: "CountInboundUsers",
+			Handler:    _MarzService_CountInboundUsers_Handler,
+		},
+		{
+			MethodName: "GetNodeInfo",
+			Handler:    _MarzService_GetNodeInfo_Handler,
+		},
+		{
+			MethodName: "ValidateBackendConfig",
+			Handler:    _MarzService_ValidateBackendConfig_Handler,
+		},
+		{
+			MethodName: "DiffBackendConfig",
+			Handler:    _MarzService_DiffBackendConfig_Handler,
+		},
+		{
+			MethodName: "AddInbound",
+			Handler:    _MarzService_AddInbound_Handler,
+		},
+		{
+			MethodName: "ReplaceInbound",
+			Handler:    _MarzService_ReplaceInbound_Handler,
+		},
+		{
+			MethodName: "RemoveInbound",
+			Handler:    _MarzService_RemoveInbound_Handler,
+		},
+		{
+			MethodName: "ListConnections",
+			Handler:    _MarzService_ListConnections_Handler,
+		},
+		{
+			MethodName: "DisconnectUser",
+			Handler:    _MarzService_DisconnectUser_Handler,
+		},
+		{
+			MethodName: "QueryAuditLog",
+			Handler:    _MarzService_QueryAuditLog_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -374,6 +942,11 @@ var MarzService_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _MarzService_SyncUsers_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "StreamUsersStats",
+			Handler:       _MarzService_StreamUsersStats_Handler,
+			ServerStreams: true,
+		},
 		{
 			StreamName:    "StreamBackendLogs",
 			Handler:       _MarzService_StreamBackendLogs_Handler,
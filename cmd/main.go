@@ -1,25 +1,38 @@
-package cmd
+package main
 
 import (
 	"context"
+	"crypto/tls"
 	"github.com/joho/godotenv"
 	"github.com/kelseyhightower/envconfig"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"log"
 	"marznode/api/pb"
 	"marznode/internal/api"
+	"marznode/internal/audit"
+	"marznode/internal/auth"
+	"marznode/internal/backends"
+	"marznode/internal/certs"
 	"marznode/internal/config"
+	"marznode/internal/gateway"
+	"marznode/internal/healthcheck"
 	"marznode/internal/repo"
 	"marznode/internal/service"
+	"marznode/internal/usage"
 	"net"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	CustomLogger "marznode/internal/logger"
 )
 
+const gatewayShutdownTimeout = 10 * time.Second
+
 func main() {
 	if err := godotenv.Load(config.EnvPath); err != nil {
 		log.Fatal("Error loading .env file", zap.Error(err))
@@ -35,6 +48,11 @@ func main() {
 		log.Fatal("Error initializing logger", zap.Error(err))
 	}
 
+	cfg.Backends, err = config.LoadBackends(cfg.BackendsFile)
+	if err != nil {
+		logger.Fatal("Error loading backends", zap.Error(err))
+	}
+
 	pool, err := repo.Connection(context.Background(), cfg.PostgresDB)
 	if err != nil {
 		logger.Error("Error connecting to postgres", zap.Error(err))
@@ -44,7 +62,7 @@ func main() {
 		logger.Error("Error to check connection to postgres", zap.Error(err))
 	}
 
-	marznodeRepository := repo.NewMarznodeRepository(pool, logger)
+	marznodeRepository := repo.NewMarznodeRepository(logger)
 
 	repos := repo.NewRepository(marznodeRepository)
 
@@ -52,12 +70,76 @@ func main() {
 
 	services := service.NewService(marznodeService)
 
-	handler := api.NewMarznodeHandler(services.MarzService, logger)
+	vpnBackends, err := backends.StartAll(context.Background(), cfg.Backends, service.NewBackendStorage(services.MarzService), logger)
+	if err != nil {
+		logger.Fatal("Error starting backends", zap.Error(err))
+	}
+
+	ledger, err := usage.NewLedger(cfg.Usage.LedgerPath, logger)
+	if err != nil {
+		logger.Fatal("Error loading usage ledger", zap.Error(err))
+	}
+
+	runCtx, stopRun := context.WithCancel(context.Background())
+	go ledger.Run(runCtx, vpnBackends, cfg.Usage.CollectInterval)
 
-	server := grpc.NewServer()
+	auditLog, err := audit.New(cfg.Audit.Path, cfg.Audit.MaxSize, cfg.Audit.MaxBackups)
+	if err != nil {
+		logger.Fatal("Error opening audit log", zap.Error(err))
+	}
+
+	handler := api.NewMarznodeHandler(services.MarzService, ledger, auditLog, logger, vpnBackends...)
+
+	// Recovery comes first so that it also covers the other interceptors.
+	unaryInterceptors := []grpc.UnaryServerInterceptor{
+		api.RecoveryUnaryInterceptor(logger),
+		api.RequestIDUnaryInterceptor(),
+		api.LoggingUnaryInterceptor(logger),
+	}
+	streamInterceptors := []grpc.StreamServerInterceptor{
+		api.RecoveryStreamInterceptor(logger),
+		api.RequestIDStreamInterceptor(),
+		api.LoggingStreamInterceptor(logger),
+	}
+
+	var authenticator *auth.Authenticator
+	switch cfg.Auth.Mode {
+	case auth.ModeMTLS:
+	case auth.ModeToken:
+		authenticator, err = auth.New(cfg.Auth, logger)
+		if err != nil {
+			logger.Fatal("Error loading auth tokens", zap.Error(err))
+		}
+		go authenticator.Run(runCtx, cfg.Auth.ReloadInterval)
+		unaryInterceptors = append(unaryInterceptors, authenticator.UnaryInterceptor())
+		streamInterceptors = append(streamInterceptors, authenticator.StreamInterceptor())
+	default:
+		logger.Fatal("Unknown auth mode", zap.String("mode", cfg.Auth.Mode))
+	}
+
+	serverOptions := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInterceptors...),
+		grpc.ChainStreamInterceptor(streamInterceptors...),
+	}
+	var tlsConfig *tls.Config
+	if cfg.Grpc.Insecure {
+		logger.Warn("Running gRPC server without TLS, client certificates are not verified")
+	} else {
+		tlsConfig, err = certs.ServerTLSConfig(cfg.Grpc, authenticator == nil)
+		if err != nil {
+			logger.Fatal("Error loading TLS config", zap.Error(err))
+		}
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+
+	server := grpc.NewServer(serverOptions...)
 
 	pb.RegisterMarzServiceServer(server, handler)
 
+	checker := healthcheck.NewChecker(logger, vpnBackends...)
+	healthpb.RegisterHealthServer(server, checker.Server())
+	go checker.Run(runCtx, cfg.Grpc.HealthCheckInterval)
+
 	lis, err := net.Listen("tcp", cfg.Grpc.Port)
 	if err != nil {
 		logger.Fatal("Failed to listen", zap.Error(err))
@@ -70,14 +152,52 @@ func main() {
 		}
 	}()
 
+	var gw *gateway.Gateway
+	if cfg.Gateway.Port != "" {
+		gatewayLis, err := net.Listen("tcp", cfg.Gateway.Port)
+		if err != nil {
+			logger.Fatal("Failed to listen for gateway", zap.Error(err))
+		}
+		if tlsConfig != nil {
+			gatewayLis = tls.NewListener(gatewayLis, tlsConfig.Clone())
+		}
+
+		gw = gateway.New(handler, authenticator, logger)
+		go func() {
+			logger.Info("starting HTTP gateway")
+			if err := gw.Serve(gatewayLis); err != nil {
+				logger.Fatal("Failed to serve gateway", zap.Error(err))
+			}
+		}()
+	}
+
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
 	<-signalChan
 
 	logger.Info("Shutting down server...")
 
+	checker.Shutdown()
+	if gw != nil {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), gatewayShutdownTimeout)
+		if err := gw.Shutdown(shutdownCtx); err != nil {
+			logger.Error("Error shutting down gateway", zap.Error(err))
+		}
+		cancel()
+	}
 	server.GracefulStop()
 
+	stopRun()
+	if err := ledger.Drain(context.Background(), vpnBackends); err != nil {
+		logger.Error("Error draining usage", zap.Error(err))
+	}
+
+	backends.StopAll(context.Background(), vpnBackends, logger)
+
+	if err := auditLog.Close(); err != nil {
+		logger.Error("Error closing audit log", zap.Error(err))
+	}
+
 	if err = repo.CloseConnection(pool); err != nil {
 		logger.Error("Error closing connection", zap.Error(err))
 	}
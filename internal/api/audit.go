@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"marznode/api/pb"
+	"marznode/internal/audit"
+	"marznode/internal/auth"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// QueryAuditLog returns the recorded state-changing calls matching the
+// query, oldest first.
+func (h *MarznodeHandler) QueryAuditLog(ctx context.Context, request *pb.AuditLogQuery) (*pb.AuditRecords, error) {
+	filter := audit.Filter{
+		RPC:     request.GetRpc(),
+		Backend: request.GetBackendName(),
+		UID:     request.Uid,
+		Limit:   int(request.GetLimit()),
+	}
+	if request.Since != nil {
+		filter.Since = time.Unix(int64(request.GetSince()), 0)
+	}
+	if request.Until != nil {
+		filter.Until = time.Unix(int64(request.GetUntil()), 0)
+	}
+
+	records, err := h.auditLog.Query(filter)
+	if err != nil {
+		return nil, toStatus(fmt.Errorf("failed to query audit log: %w", err), nil)
+	}
+
+	response := &pb.AuditRecords{Records: make([]*pb.AuditRecord, 0, len(records))}
+	for _, record := range records {
+		pbRecord := &pb.AuditRecord{
+			Time:      uint64(record.Time.Unix()),
+			Caller:    record.Caller,
+			Peer:      record.Peer,
+			Rpc:       record.RPC,
+			RequestId: record.RequestID,
+			Users:     record.Users,
+			Backends:  record.Backends,
+			Inbounds:  record.Inbounds,
+			Outcome:   record.Outcome,
+		}
+		if record.Error != "" {
+			pbRecord.Error = &record.Error
+		}
+		if record.UsageCursor != "" {
+			pbRecord.UsageCursor = &record.UsageCursor
+		}
+		response.Records = append(response.Records, pbRecord)
+	}
+	return response, nil
+}
+
+// audit records a state-changing call. err is the error returned to the
+// caller.
+func (h *MarznodeHandler) audit(ctx context.Context, record audit.Record, err error) {
+	record.Time = time.Now()
+	record.Caller, record.Peer = callerIdentity(ctx)
+	record.RequestID = RequestID(ctx)
+	st := status.Convert(err)
+	record.Outcome = st.Code().String()
+	if err != nil {
+		record.Error = st.Message()
+	}
+
+	if writeErr := h.auditLog.Write(record); writeErr != nil {
+		h.logger(ctx).Errorf("Failed to write audit record for %s: %v", record.RPC, writeErr)
+	}
+}
+
+// callerIdentity returns the token or the subject of the client certificate
+// the caller authenticated with, or the peer address without either, along
+// with the peer address.
+func callerIdentity(ctx context.Context) (string, string) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown", ""
+	}
+
+	if subject := auth.Subject(ctx); subject != "" {
+		return subject, p.Addr.String()
+	}
+
+	if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+		return tlsInfo.State.PeerCertificates[0].Subject.String(), p.Addr.String()
+	}
+	return p.Addr.String(), p.Addr.String()
+}
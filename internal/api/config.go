@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"marznode/api/pb"
+	"marznode/internal/service"
+	"marznode/pkg/backend/common"
+)
+
+// ValidateBackendConfig checks a config with the backend's own parser and
+// the core's check mode, without touching the running process. Without a
+// config, the one on disk is checked.
+func (h *MarznodeHandler) ValidateBackendConfig(ctx context.Context, request *pb.ValidateBackendConfigRequest) (*pb.ValidateBackendConfigResponse, error) {
+	backend, err := h.backendByName(request.GetBackendName())
+	if err != nil {
+		return nil, err
+	}
+
+	var config any
+	if request.Config != nil {
+		config = request.GetConfig().GetConfiguration()
+	}
+
+	err = backend.ValidateConfig(ctx, config)
+
+	var configErr *common.ConfigError
+	switch {
+	case err == nil:
+		return &pb.ValidateBackendConfigResponse{Valid: true}, nil
+	case errors.As(err, &configErr):
+		response := &pb.ValidateBackendConfigResponse{}
+		for _, message := range configErr.Messages {
+			response.Errors = append(response.Errors, &pb.ConfigError{
+				Stage:   configErr.Stage,
+				Message: message,
+			})
+		}
+		return response, nil
+	default:
+		return nil, toStatus(fmt.Errorf("failed to validate config of backend %s: %w", request.GetBackendName(), err), backendMetadata(request.GetBackendName()))
+	}
+}
+
+// DiffBackendConfig compares a proposed config with the one the backend has
+// on disk.
+func (h *MarznodeHandler) DiffBackendConfig(ctx context.Context, request *pb.DiffBackendConfigRequest) (*pb.BackendConfigDiff, error) {
+	backend, err := h.backendByName(request.GetBackendName())
+	if err != nil {
+		return nil, err
+	}
+	if request.GetConfig().GetConfiguration() == "" {
+		return nil, toStatus(fmt.Errorf("%w: config is required", service.ErrInvalidArgument), backendMetadata(request.GetBackendName()))
+	}
+
+	diff, err := backend.DiffConfig(ctx, request.GetConfig().GetConfiguration())
+	if err != nil {
+		return nil, toStatus(fmt.Errorf("failed to diff config of backend %s: %w", request.GetBackendName(), err), backendMetadata(request.GetBackendName()))
+	}
+
+	return &pb.BackendConfigDiff{
+		Added:           inboundDiffsToProto(diff.Added),
+		Removed:         inboundDiffsToProto(diff.Removed),
+		Modified:        inboundDiffsToProto(diff.Modified),
+		ChangedSections: diff.ChangedSections,
+		RestartRequired: diff.RestartRequired,
+		ReloadRequired:  diff.ReloadRequired,
+	}, nil
+}
+
+func inboundDiffsToProto(diffs []common.InboundDiff) []*pb.InboundDiff {
+	pbDiffs := make([]*pb.InboundDiff, 0, len(diffs))
+	for _, diff := range diffs {
+		pbDiff := &pb.InboundDiff{
+			Tag:           diff.Tag,
+			Protocol:      diff.Protocol,
+			PortChanged:   diff.PortChanged,
+			TlsChanged:    diff.TLSChanged,
+			ChangedFields: diff.ChangedFields,
+		}
+		if diff.OldPort != 0 {
+			oldPort := uint32(diff.OldPort)
+			pbDiff.OldPort = &oldPort
+		}
+		if diff.NewPort != 0 {
+			newPort := uint32(diff.NewPort)
+			pbDiff.NewPort = &newPort
+		}
+		pbDiffs = append(pbDiffs, pbDiff)
+	}
+	return pbDiffs
+}
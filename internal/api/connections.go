@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"marznode/api/pb"
+	"marznode/internal/audit"
+	"marznode/pkg/backend/common"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ListConnections returns the active connections of the running backends,
+// grouped by user. Connections the core doesn't attribute to a user are
+// grouped under an empty user.
+func (h *MarznodeHandler) ListConnections(ctx context.Context, request *pb.ListConnectionsRequest) (*pb.ConnectionsResponse, error) {
+	backends := h.backends
+	if request.BackendName != nil {
+		backend, err := h.backendByName(request.GetBackendName())
+		if err != nil {
+			return nil, err
+		}
+		backends = []common.VPNBackend{backend}
+	}
+
+	users := make(map[string]*pb.UserConnections)
+	for _, backend := range backends {
+		if !backend.Running() {
+			continue
+		}
+
+		connections, err := backend.ListConnections(ctx)
+		if err != nil {
+			if request.BackendName != nil {
+				return nil, toStatus(fmt.Errorf("failed to list connections of backend %s: %w", backend.Name(), err), backendMetadata(backend.Name()))
+			}
+			h.logger(ctx).Errorf("Failed to list connections of backend %s: %v", backend.Name(), err)
+			continue
+		}
+
+		for _, connection := range connections {
+			uid, hasUID := userID(connection.User)
+			if request.Uid != nil && (!hasUID || uid != request.GetUid()) {
+				continue
+			}
+
+			user, ok := users[connection.User]
+			if !ok {
+				user = &pb.UserConnections{User: connection.User}
+				if hasUID {
+					user.Uid = &uid
+				}
+				users[connection.User] = user
+			}
+			user.Connections = append(user.Connections, connectionToProto(backend.Name(), connection))
+		}
+	}
+
+	response := &pb.ConnectionsResponse{Users: make([]*pb.UserConnections, 0, len(users))}
+	for _, user := range users {
+		response.Users = append(response.Users, user)
+	}
+	slices.SortFunc(response.Users, func(a, b *pb.UserConnections) int {
+		return strings.Compare(a.GetUser(), b.GetUser())
+	})
+	return response, nil
+}
+
+// DisconnectUser closes the connections of a user on every running backend.
+// The user doesn't have to be known to the node anymore.
+func (h *MarznodeHandler) DisconnectUser(ctx context.Context, request *pb.UserRequest) (_ *pb.DisconnectUserResponse, err error) {
+	defer func() {
+		h.audit(ctx, audit.Record{RPC: "DisconnectUser", Users: []uint32{request.GetUid()}}, err)
+	}()
+
+	h.logger(ctx).Infof("Disconnecting user %d", request.GetUid())
+
+	response := &pb.DisconnectUserResponse{}
+	var errs []error
+	for _, backend := range h.backends {
+		if !backend.Running() {
+			continue
+		}
+
+		closed, err := backend.DisconnectUser(ctx, int64(request.GetUid()))
+		response.ClosedConnections += uint32(closed)
+		if err != nil {
+			h.logger(ctx).Errorf("Failed to disconnect user %d on backend %s: %v", request.GetUid(), backend.Name(), err)
+			errs = append(errs, fmt.Errorf("backend %s: %w", backend.Name(), err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, toStatus(fmt.Errorf("failed to disconnect user %d: %w", request.GetUid(), err), userMetadata(request.GetUid()))
+	}
+	return response, nil
+}
+
+func connectionToProto(backendName string, connection common.Connection) *pb.Connection {
+	pbConnection := &pb.Connection{
+		Id:          connection.ID,
+		BackendName: backendName,
+		InboundTag:  connection.InboundTag,
+		Network:     connection.Network,
+		Source:      connection.Source,
+		Destination: connection.Destination,
+		Upload:      uint64(connection.Upload),
+		Download:    uint64(connection.Download),
+	}
+	if !connection.Start.IsZero() {
+		pbConnection.StartedAt = uint64(connection.Start.Unix())
+	}
+	return pbConnection
+}
+
+// userID returns the uid of an "id.username" account identifier.
+func userID(identifier string) (uint32, bool) {
+	id, _, _ := strings.Cut(identifier, ".")
+	uid, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return 0, false
+	}
+	return uint32(uid), true
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"marznode/internal/audit"
+	"marznode/internal/service"
+	"marznode/pkg/backend/common"
+	"strconv"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const errorDomain = "marznode"
+
+// Reasons attached to error statuses as ErrorInfo details, so the panel can
+// react to an error without parsing its message.
+const (
+	ReasonNotFound          = "NOT_FOUND"
+	ReasonAlreadyExists     = "ALREADY_EXISTS"
+	ReasonInvalidArgument   = "INVALID_ARGUMENT"
+	ReasonInvalidConfig     = "INVALID_CONFIG"
+	ReasonBackendBusy       = "BACKEND_BUSY"
+	ReasonBackendNotRunning = "BACKEND_NOT_RUNNING"
+	ReasonAuditDisabled     = "AUDIT_DISABLED"
+	ReasonInternal          = "INTERNAL"
+)
+
+type errorKind struct {
+	target error
+	code   codes.Code
+	reason string
+}
+
+// errorKinds is checked in order, so more specific errors come first.
+var errorKinds = []errorKind{
+	{service.ErrNotFound, codes.NotFound, ReasonNotFound},
+	{service.ErrAlreadyExists, codes.AlreadyExists, ReasonAlreadyExists},
+	{common.ErrInboundNotFound, codes.NotFound, ReasonNotFound},
+	{common.ErrInboundAlreadyExists, codes.AlreadyExists, ReasonAlreadyExists},
+	{service.ErrInvalidArgument, codes.InvalidArgument, ReasonInvalidArgument},
+	{common.ErrInvalidConfig, codes.InvalidArgument, ReasonInvalidConfig},
+	{common.ErrProcessAlreadyRestarting, codes.Unavailable, ReasonBackendBusy},
+	{common.ErrProcessAlreadyRunning, codes.Unavailable, ReasonBackendBusy},
+	{common.ErrProcessNotRunning, codes.FailedPrecondition, ReasonBackendNotRunning},
+	{common.ErrConfigNotSet, codes.FailedPrecondition, ReasonBackendNotRunning},
+	{audit.ErrDisabled, codes.FailedPrecondition, ReasonAuditDisabled},
+}
+
+// toStatus converts an error from the service or backend layers into a gRPC
+// status carrying an ErrorInfo detail. Errors that already are statuses are
+// returned as is.
+func toStatus(err error, metadata map[string]string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	code, reason := codes.Internal, ReasonInternal
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind.target) {
+			code, reason = kind.code, kind.reason
+			break
+		}
+	}
+
+	st, detailsErr := status.New(code, err.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   errorDomain,
+		Metadata: metadata,
+	})
+	if detailsErr != nil {
+		return status.Error(code, err.Error())
+	}
+	return st.Err()
+}
+
+func backendMetadata(name string) map[string]string {
+	return map[string]string{"backend": name}
+}
+
+func userMetadata(uid uint32) map[string]string {
+	return map[string]string{"uid": strconv.FormatUint(uint64(uid), 10)}
+}
+
+func inboundMetadata(tag string) map[string]string {
+	return map[string]string{"tag": tag}
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"marznode/internal/audit"
+	"marznode/internal/service"
+	"marznode/pkg/backend/common"
+	"reflect"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{name: "user not found", err: service.ErrNotFound, code: codes.NotFound, reason: ReasonNotFound},
+		{name: "user exists", err: service.ErrAlreadyExists, code: codes.AlreadyExists, reason: ReasonAlreadyExists},
+		{name: "inbound not found", err: common.ErrInboundNotFound, code: codes.NotFound, reason: ReasonNotFound},
+		{name: "inbound exists", err: common.ErrInboundAlreadyExists, code: codes.AlreadyExists, reason: ReasonAlreadyExists},
+		{name: "invalid argument", err: service.ErrInvalidArgument, code: codes.InvalidArgument, reason: ReasonInvalidArgument},
+		{name: "invalid config", err: common.ErrInvalidConfig, code: codes.InvalidArgument, reason: ReasonInvalidConfig},
+		{name: "restarting", err: common.ErrProcessAlreadyRestarting, code: codes.Unavailable, reason: ReasonBackendBusy},
+		{name: "running", err: common.ErrProcessAlreadyRunning, code: codes.Unavailable, reason: ReasonBackendBusy},
+		{name: "not running", err: common.ErrProcessNotRunning, code: codes.FailedPrecondition, reason: ReasonBackendNotRunning},
+		{name: "config not set", err: common.ErrConfigNotSet, code: codes.FailedPrecondition, reason: ReasonBackendNotRunning},
+		{name: "audit disabled", err: audit.ErrDisabled, code: codes.FailedPrecondition, reason: ReasonAuditDisabled},
+		{name: "unknown", err: errors.New("disk on fire"), code: codes.Internal, reason: ReasonInternal},
+		{name: "more specific first", err: errors.Join(service.ErrNotFound, common.ErrProcessNotRunning), code: codes.NotFound, reason: ReasonNotFound},
+	}
+
+	covered := make(map[error]bool)
+	for _, tt := range tests {
+		covered[tt.err] = true
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("failed to add user 1 to backend first: %w", tt.err)
+			metadata := userMetadata(1)
+
+			st, ok := status.FromError(toStatus(err, metadata))
+			if !ok {
+				t.Fatalf("expected a status, got %v", err)
+			}
+			if st.Code() != tt.code {
+				t.Errorf("expected code %s, got %s", tt.code, st.Code())
+			}
+			if st.Message() != err.Error() {
+				t.Errorf("expected message %q, got %q", err.Error(), st.Message())
+			}
+
+			details := st.Details()
+			if len(details) != 1 {
+				t.Fatalf("expected one detail, got %v", details)
+			}
+			info, ok := details[0].(*errdetails.ErrorInfo)
+			if !ok {
+				t.Fatalf("expected ErrorInfo, got %T", details[0])
+			}
+			if info.Reason != tt.reason || info.Domain != errorDomain {
+				t.Errorf("expected reason %s in %s, got %s in %s", tt.reason, errorDomain, info.Reason, info.Domain)
+			}
+			if !reflect.DeepEqual(info.Metadata, metadata) {
+				t.Errorf("expected metadata %v, got %v", metadata, info.Metadata)
+			}
+		})
+	}
+
+	for _, kind := range errorKinds {
+		if !covered[kind.target] {
+			t.Errorf("expected a case for %v", kind.target)
+		}
+	}
+}
+
+func TestToStatus_Passthrough(t *testing.T) {
+	if err := toStatus(nil, nil); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+
+	existing := status.Error(codes.PermissionDenied, "denied")
+	if err := toStatus(existing, nil); err != existing {
+		t.Errorf("expected the status to be returned as is, got %v", err)
+	}
+
+	tests := []struct {
+		err  error
+		code codes.Code
+	}{
+		{err: context.Canceled, code: codes.Canceled},
+		{err: context.DeadlineExceeded, code: codes.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		err := toStatus(fmt.Errorf("failed to sync users: %w", tt.err), nil)
+		if code := status.Code(err); code != tt.code {
+			t.Errorf("expected code %s for %v, got %s", tt.code, tt.err, code)
+		}
+		if st, _ := status.FromError(err); len(st.Details()) != 0 {
+			t.Errorf("expected no details for %v, got %v", tt.err, st.Details())
+		}
+	}
+}
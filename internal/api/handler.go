@@ -3,10 +3,17 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"marznode/api/pb"
+	"marznode/internal/audit"
 	"marznode/internal/service"
+	"marznode/internal/usage"
 	"marznode/pkg/backend/common"
+	"marznode/pkg/backend/common/models"
+	"sync"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -14,51 +21,128 @@ import (
 
 type MarznodeHandler struct {
 	marznode service.MarznodeMemory
+	ledger   *usage.Ledger
+	auditLog *audit.Log
 	log      *zap.SugaredLogger
 	pb.UnimplementedMarzServiceServer
 	backends []common.VPNBackend
+	usersMu  sync.Mutex
+	// restartLocks holds a *sync.Mutex per backend name.
+	restartLocks sync.Map
 }
 
-func NewMarznodeHandler(marznode service.MarznodeMemory, log *zap.SugaredLogger, backend ...common.VPNBackend) *MarznodeHandler {
+func NewMarznodeHandler(marznode service.MarznodeMemory, ledger *usage.Ledger, auditLog *audit.Log, log *zap.SugaredLogger, backend ...common.VPNBackend) *MarznodeHandler {
 	return &MarznodeHandler{
 		marznode: marznode,
+		ledger:   ledger,
+		auditLog: auditLog,
 		log:      log,
 		backends: backend,
 	}
 }
 
-func (h *MarznodeHandler) SyncUsers(server grpc.ClientStreamingServer[pb.UserData, pb.Empty]) error {
+func (h *MarznodeHandler) SyncUsers(server grpc.ClientStreamingServer[pb.UserData, pb.SyncUsersResponse]) error {
+	ctx := server.Context()
+	response := &pb.SyncUsersResponse{}
+
 	for {
 		userData, err := server.Recv()
 		if err == io.EOF {
-			return server.SendAndClose(&pb.Empty{})
+			return server.SendAndClose(response)
 		}
 		if err != nil {
 			return err
 		}
 
-		h.log.Infof("Received user data: %v", userData)
+		h.usersMu.Lock()
+		change, err := h.updateUser(ctx, userFromProto(userData.GetUser()), userData.GetInbounds())
+		h.usersMu.Unlock()
+
+		if change != userUnchanged || err != nil {
+			h.audit(ctx, audit.Record{RPC: "SyncUsers", Users: []uint32{userData.GetUser().GetId()}}, toStatus(err, nil))
+		}
+		if err != nil {
+			h.logger(ctx).Errorf("Failed to sync user %d: %v", userData.GetUser().GetId(), err)
+			response.Errors = append(response.Errors, &pb.UserError{
+				Uid:   userData.GetUser().GetId(),
+				Error: err.Error(),
+			})
+		}
 	}
 }
 
-func (h *MarznodeHandler) RepopulateUsers(ctx context.Context, user *pb.UsersData) (*pb.Empty, error) {
+func (h *MarznodeHandler) RepopulateUsers(ctx context.Context, usersData *pb.UsersData) (_ *pb.RepopulateUsersResponse, err error) {
+	h.usersMu.Lock()
+	defer h.usersMu.Unlock()
+
+	var affected []uint32
+	response := &pb.RepopulateUsersResponse{}
+	defer func() {
+		record := audit.Record{RPC: "RepopulateUsers", Users: affected}
+		if len(response.Errors) > 0 {
+			record.Error = fmt.Sprintf("failed to update %d users", len(response.Errors))
+		}
+		h.audit(ctx, record, err)
+	}()
 
-	return nil, nil
+	update := func(user models.User, inbounds []*pb.Inbound) {
+		change, err := h.updateUser(ctx, user, inbounds)
+		if err != nil {
+			h.logger(ctx).Errorf("Failed to repopulate user %d: %v", user.ID, err)
+			response.Errors = append(response.Errors, &pb.UserError{
+				Uid:   uint32(user.ID),
+				Error: err.Error(),
+			})
+		}
+		if change != userUnchanged {
+			affected = append(affected, uint32(user.ID))
+		}
+		switch change {
+		case userAdded:
+			response.Added++
+		case userRemoved:
+			response.Removed++
+		case userChanged:
+			response.Changed++
+		}
+	}
+
+	userIDs := make(map[int64]struct{}, len(usersData.GetUsersData()))
+	for _, userData := range usersData.GetUsersData() {
+		userIDs[int64(userData.GetUser().GetId())] = struct{}{}
+		update(userFromProto(userData.GetUser()), userData.GetInbounds())
+	}
+
+	storageUsers, err := h.marznode.ListUsers(ctx)
+	if err != nil {
+		return nil, toStatus(fmt.Errorf("failed to list users: %w", err), nil)
+	}
+
+	for _, user := range storageUsers {
+		if _, ok := userIDs[user.ID]; !ok {
+			update(user, nil)
+		}
+	}
+
+	h.logger(ctx).Infof("Repopulated users: %d added, %d removed, %d changed, %d failed",
+		response.Added, response.Removed, response.Changed, len(response.Errors))
+
+	return response, nil
 }
 
 func (h *MarznodeHandler) FetchBackends(ctx context.Context, empty *pb.Empty) (*pb.BackendsResponse, error) {
 	var backends []*pb.Backend
 
-	for i, backend := range h.backends {
+	for _, backend := range h.backends {
 		version, err := backend.Version()
 		if err != nil {
-			h.log.Warnf("Failed to get version for backend %d: %v", i, err)
+			h.logger(ctx).Warnf("Failed to get version for backend %s: %v", backend.Name(), err)
 			version = "unknown"
 		}
 
 		inbounds, err := backend.ListInbounds(ctx)
 		if err != nil {
-			h.log.Errorf("Failed to get inbounds for backend %d: %v", i, err)
+			h.logger(ctx).Errorf("Failed to get inbounds for backend %s: %v", backend.Name(), err)
 			continue
 		}
 
@@ -66,7 +150,7 @@ func (h *MarznodeHandler) FetchBackends(ctx context.Context, empty *pb.Empty) (*
 		for _, inbound := range inbounds {
 			configJSON, err := json.Marshal(inbound.Config)
 			if err != nil {
-				h.log.Warnf("Failed to marshal config for inbound %s: %v", inbound.Tag, err)
+				h.logger(ctx).Warnf("Failed to marshal config for inbound %s: %v", inbound.Tag, err)
 				continue
 			}
 
@@ -80,7 +164,7 @@ func (h *MarznodeHandler) FetchBackends(ctx context.Context, empty *pb.Empty) (*
 
 		backendType := backend.BackendType()
 		pbBackend := &pb.Backend{
-			Name:     backendType,
+			Name:     backend.Name(),
 			Type:     &backendType,
 			Version:  &version,
 			Inbounds: pbInbounds,
@@ -94,45 +178,497 @@ func (h *MarznodeHandler) FetchBackends(ctx context.Context, empty *pb.Empty) (*
 	}, nil
 }
 
+// FetchUsersStats returns the traffic collected so far and acknowledges it
+// right away. Use CollectUsersStats for lossless accounting.
 func (h *MarznodeHandler) FetchUsersStats(ctx context.Context, empty *pb.Empty) (*pb.UsersStats, error) {
-	var allUserStats []*pb.UsersStats_UserStats
+	stats, err := h.collectUsersStats(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, backend := range h.backends {
-		stats, err := backend.GetUsages(ctx)
-		if err != nil {
+	if err := h.ackUsage(ctx, "FetchUsersStats", stats.Cursor); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// CollectUsersStats returns all traffic not acknowledged yet. The panel
+// acknowledges a response by sending its cursor with the next collection.
+func (h *MarznodeHandler) CollectUsersStats(ctx context.Context, request *pb.CollectUsersStatsRequest) (*pb.UsersStats, error) {
+	if cursor := request.GetAckCursor(); cursor != "" {
+		if err := h.ackUsage(ctx, "CollectUsersStats", cursor); err != nil {
 			return nil, err
 		}
-		if usageMap, ok := stats.(map[int64]int64); ok {
-			for uid, usage := range usageMap {
-				allUserStats = append(allUserStats, &pb.UsersStats_UserStats{
-					Uid:   uint32(uid),
-					Usage: uint64(usage),
-				})
+	}
+
+	return h.collectUsersStats(ctx)
+}
+
+// StreamUsersStats pushes the traffic collected since the previous message
+// every interval. Messages carry deltas: a consumer that falls behind gets
+// the skipped collections merged into the next message. Cursors are
+// acknowledged through AckUsersStats, or by resuming a stream from them.
+func (h *MarznodeHandler) StreamUsersStats(request *pb.StreamUsersStatsRequest, stream grpc.ServerStreamingServer[pb.UsersStats]) error {
+	ctx := stream.Context()
+
+	interval := time.Duration(request.GetIntervalSeconds()) * time.Second
+	if interval < minStatsInterval {
+		interval = minStatsInterval
+	}
+
+	cursor := request.GetResumeCursor()
+	if cursor != "" {
+		if err := h.ackUsage(ctx, "StreamUsersStats", cursor); err != nil {
+			return err
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := h.ledger.Drain(ctx, h.backends); err != nil {
+			h.logger(ctx).Errorf("Failed to collect usage: %v", err)
+		}
+		if err := h.ledger.Seal(); err != nil {
+			return toStatus(fmt.Errorf("failed to collect usage: %w", err), nil)
+		}
+
+		next, entries, err := h.ledger.Since(cursor)
+		if err != nil {
+			return toStatus(fmt.Errorf("failed to collect usage: %w: %v", service.ErrInvalidArgument, err), nil)
+		}
+
+		if len(entries) > 0 {
+			if err := sendWithTimeout(ctx, stream, usersStatsToProto(next, entries), slowConsumerTimeout(interval)); err != nil {
+				return err
 			}
+			cursor = next
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
 		}
 	}
+}
 
-	return &pb.UsersStats{
-		UsersStats: allUserStats,
-	}, nil
+func (h *MarznodeHandler) AckUsersStats(ctx context.Context, request *pb.AckUsersStatsRequest) (*pb.Empty, error) {
+	if err := h.ackUsage(ctx, "AckUsersStats", request.GetCursor()); err != nil {
+		return nil, err
+	}
+	return &pb.Empty{}, nil
 }
 
-func (h *MarznodeHandler) FetchBackendConfig(ctx context.Context, backend *pb.Backend) (*pb.BackendConfig, error) {
+// ackUsage acknowledges a usage cursor. It drops the traffic the cursor
+// covers from the ledger, so it is audited.
+func (h *MarznodeHandler) ackUsage(ctx context.Context, rpc, cursor string) (err error) {
+	defer func() {
+		h.audit(ctx, audit.Record{RPC: rpc, UsageCursor: cursor}, err)
+	}()
+
+	if err := h.ledger.Ack(cursor); err != nil {
+		if errors.Is(err, usage.ErrInvalidCursor) {
+			err = fmt.Errorf("%w: %v", service.ErrInvalidArgument, err)
+		}
+		return toStatus(fmt.Errorf("failed to acknowledge usage: %w", err), nil)
+	}
+	return nil
+}
+
+func (h *MarznodeHandler) collectUsersStats(ctx context.Context) (*pb.UsersStats, error) {
+	if err := h.ledger.Drain(ctx, h.backends); err != nil {
+		return nil, toStatus(fmt.Errorf("failed to collect usage: %w", err), nil)
+	}
+
+	cursor, entries, err := h.ledger.Collect()
+	if err != nil {
+		return nil, toStatus(fmt.Errorf("failed to collect usage: %w", err), nil)
+	}
+
+	return usersStatsToProto(cursor, entries), nil
+}
+
+func (h *MarznodeHandler) FetchBackendConfig(ctx context.Context, request *pb.Backend) (*pb.BackendConfig, error) {
+	backend, err := h.backendByName(request.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := backend.GetConfig(ctx)
+	if err != nil {
+		return nil, toStatus(fmt.Errorf("failed to get config of backend %s: %w", request.GetName(), err), backendMetadata(request.GetName()))
+	}
+
+	var configuration string
+	switch cfg := config.(type) {
+	case string:
+		configuration = cfg
+	case []byte:
+		configuration = string(cfg)
+	default:
+		data, err := json.Marshal(cfg)
+		if err != nil {
+			return nil, toStatus(fmt.Errorf("failed to marshal config of backend %s: %w", request.GetName(), err), backendMetadata(request.GetName()))
+		}
+		configuration = string(data)
+	}
 
-	return nil, nil
+	return &pb.BackendConfig{
+		Configuration: configuration,
+		ConfigFormat:  configFormatToProto(backend.ConfigFormat()),
+	}, nil
 }
 
-func (h *MarznodeHandler) RestartBackend(ctx context.Context, request *pb.RestartBackendRequest) (*pb.Empty, error) {
+func (h *MarznodeHandler) RestartBackend(ctx context.Context, request *pb.RestartBackendRequest) (_ *pb.Empty, err error) {
+	defer func() {
+		h.audit(ctx, audit.Record{RPC: "RestartBackend", Backends: []string{request.GetBackendName()}}, err)
+	}()
+
+	backend, err := h.backendByName(request.GetBackendName())
+	if err != nil {
+		return nil, err
+	}
+
+	var config any
+	if request.Config != nil {
+		config = request.GetConfig().GetConfiguration()
+	}
 
-	return nil, nil
+	lock := h.restartLock(request.GetBackendName())
+	lock.Lock()
+	defer lock.Unlock()
+
+	h.logger(ctx).Infof("Restarting backend %s", request.GetBackendName())
+
+	if err := backend.Restart(ctx, config); err != nil {
+		h.logger(ctx).Errorf("Failed to restart backend %s: %v", request.GetBackendName(), err)
+		return nil, toStatus(fmt.Errorf("failed to restart backend %s: %w", request.GetBackendName(), err), backendMetadata(request.GetBackendName()))
+	}
+
+	return &pb.Empty{}, nil
 }
 
 func (h *MarznodeHandler) StreamBackendLogs(request *pb.BackendLogsRequest, client grpc.ServerStreamingServer[pb.LogLine]) error {
+	backend, err := h.backendByName(request.GetBackendName())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(client.Context())
+	defer cancel()
+
+	// The channel is closed once the client goes away or the backend process
+	// exits, which ends the stream. Subscribing before checking the process
+	// means an exit in between still closes it.
+	logs, err := backend.GetLogs(ctx, request.GetIncludeBuffer())
+	if err != nil {
+		return toStatus(fmt.Errorf("failed to get logs of backend %s: %w", request.GetBackendName(), err), backendMetadata(request.GetBackendName()))
+	}
+
+	if !backend.Running() {
+		return toStatus(fmt.Errorf("backend %s: %w", request.GetBackendName(), common.ErrProcessNotRunning), backendMetadata(request.GetBackendName()))
+	}
+
+	for line := range logs {
+		if err := client.Send(&pb.LogLine{Line: line}); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-func (h *MarznodeHandler) GetBackendStats(ctx context.Context, backend *pb.Backend) (*pb.BackendStats, error) {
+func (h *MarznodeHandler) GetBackendStats(ctx context.Context, request *pb.Backend) (*pb.BackendStats, error) {
+	backend, err := h.backendByName(request.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := backend.GetStats(ctx)
+	if err != nil {
+		return nil, toStatus(fmt.Errorf("failed to get stats of backend %s: %w", request.GetName(), err), backendMetadata(request.GetName()))
+	}
+
+	response := &pb.BackendStats{
+		Running:  stats.Running,
+		Restarts: uint64(stats.Restarts),
+	}
+	if stats.LastExitReason != "" {
+		response.LastExitReason = &stats.LastExitReason
+	}
+	if stats.Running {
+		pid := uint32(stats.PID)
+		uptime := uint64(stats.Uptime.Seconds())
+		response.Pid = &pid
+		response.Uptime = &uptime
+		if stats.RSS > 0 {
+			response.Rss = &stats.RSS
+		}
+	}
+	if stats.Runtime != nil {
+		response.CoreStats = &pb.CoreRuntimeStats{
+			NumGoroutine: stats.Runtime.NumGoroutine,
+			NumGc:        stats.Runtime.NumGC,
+			Alloc:        stats.Runtime.Alloc,
+			TotalAlloc:   stats.Runtime.TotalAlloc,
+			Sys:          stats.Runtime.Sys,
+			Mallocs:      stats.Runtime.Mallocs,
+			Frees:        stats.Runtime.Frees,
+			LiveObjects:  stats.Runtime.LiveObjects,
+			PauseTotalNs: stats.Runtime.PauseTotalNs,
+			Uptime:       stats.Runtime.Uptime,
+		}
+	}
+
+	return response, nil
+}
+
+type userChange int
+
+const (
+	userUnchanged userChange = iota
+	userAdded
+	userRemoved
+	userChanged
+)
+
+// updateUser brings the user in storage and on the backends in line with the
+// given inbounds. An empty inbound list removes the user from the node.
+// Backends are updated first and storage records the inbounds they ended up
+// with, so inbounds that failed are retried on the next sync. A changed key or
+// username re-adds the user to all of its inbounds.
+func (h *MarznodeHandler) updateUser(ctx context.Context, user models.User, inbounds []*pb.Inbound) (userChange, error) {
+	storageUser, err := h.marznode.GetUser(ctx, user.ID)
+	if errors.Is(err, service.ErrUserNotFound) {
+		storageUser = nil
+	} else if err != nil {
+		return userUnchanged, err
+	}
+	if storageUser == nil && len(inbounds) == 0 {
+		return userUnchanged, nil
+	}
+
+	inboundTags := make(map[string]struct{}, len(inbounds))
+	for _, inbound := range inbounds {
+		inboundTags[inbound.GetTag()] = struct{}{}
+	}
+
+	var currentInbounds []models.Inbound
+	storageTags := make(map[string]struct{})
+	if storageUser != nil {
+		currentInbounds = storageUser.Inbounds
+		for _, inbound := range currentInbounds {
+			storageTags[inbound.Tag] = struct{}{}
+		}
+	}
+
+	addedTags := tagsDifference(inboundTags, storageTags)
+	removedTags := tagsDifference(storageTags, inboundTags)
+	credentialsChanged := storageUser != nil && len(inbounds) > 0 &&
+		(storageUser.Key != user.Key || storageUser.Username != user.Username)
+	if credentialsChanged {
+		// Backends can't update a user in place, so the user is removed from
+		// every inbound and added back with the new credentials.
+		addedTags = tagsDifference(inboundTags, nil)
+		removedTags = tagsDifference(storageTags, nil)
+	} else if storageUser != nil && len(inbounds) > 0 && len(addedTags) == 0 && len(removedTags) == 0 {
+		// A user without inbounds is still dropped from storage below.
+		return userUnchanged, nil
+	}
+
+	addedInbounds, err := h.marznode.ListInbounds(ctx, addedTags, false)
+	if err != nil {
+		return userUnchanged, err
+	}
+
+	var errs []error
+	removed := make(map[string]struct{})
+	for _, inbound := range filterInbounds(currentInbounds, removedTags) {
+		// Backends identify the user by the credentials they were added with.
+		if err := h.removeUser(ctx, *storageUser, inbound); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed[inbound.Tag] = struct{}{}
+	}
+
+	var userInbounds []models.Inbound
+	for _, inbound := range currentInbounds {
+		if _, ok := removed[inbound.Tag]; !ok {
+			userInbounds = append(userInbounds, inbound)
+		}
+	}
+
+	backendUser := user
+	switch {
+	case credentialsChanged && len(errs) > 0:
+		// The inbounds still holding the old credentials stay recorded under
+		// them, and the next sync retries the change.
+		backendUser, addedInbounds = *storageUser, nil
+	case storageUser != nil && !credentialsChanged:
+		backendUser = *storageUser
+	}
+	for _, inbound := range addedInbounds {
+		if err := h.addUser(ctx, backendUser, inbound); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		userInbounds = append(userInbounds, inbound)
+	}
+
+	change := userChanged
+	switch {
+	case len(userInbounds) == 0 && storageUser == nil:
+		return userUnchanged, errors.Join(errs...)
+	case len(userInbounds) == 0:
+		if err := h.marznode.RemoveUser(ctx, *storageUser); err != nil {
+			return userChanged, errors.Join(append(errs, err)...)
+		}
+		return userRemoved, errors.Join(errs...)
+	case storageUser == nil:
+		change = userAdded
+	}
+
+	if err := h.marznode.UpdateUserInbounds(ctx, backendUser, userInbounds); err != nil {
+		errs = append(errs, err)
+	}
+	return change, errors.Join(errs...)
+}
+
+func (h *MarznodeHandler) addUser(ctx context.Context, user models.User, inbound models.Inbound) error {
+	backend, err := h.resolveTag(inbound.Tag)
+	if err != nil {
+		return err
+	}
+	h.logger(ctx).Debugf("Adding user %s to inbound %s", user.Username, inbound.Tag)
+	if err := backend.AddUser(ctx, user, inbound); err != nil {
+		return fmt.Errorf("failed to add user %d to inbound %s: %w", user.ID, inbound.Tag, err)
+	}
+	return nil
+}
+
+// removeUser removes the user from an inbound. An inbound no backend has
+// anymore has no users to remove.
+func (h *MarznodeHandler) removeUser(ctx context.Context, user models.User, inbound models.Inbound) error {
+	backend, err := h.resolveTag(inbound.Tag)
+	if errors.Is(err, service.ErrInboundNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	h.logger(ctx).Debugf("Removing user %s from inbound %s", user.Username, inbound.Tag)
+	if err := backend.RemoveUser(ctx, user, inbound); err != nil {
+		return fmt.Errorf("failed to remove user %d from inbound %s: %w", user.ID, inbound.Tag, err)
+	}
+	return nil
+}
+
+// logger returns the handler logger tagged with the request ID, if any, so
+// backend log lines can be correlated with the RPC that caused them.
+func (h *MarznodeHandler) logger(ctx context.Context) *zap.SugaredLogger {
+	if id := RequestID(ctx); id != "" {
+		return h.log.With("request_id", id)
+	}
+	return h.log
+}
+
+func (h *MarznodeHandler) backendByName(name string) (common.VPNBackend, error) {
+	for _, backend := range h.backends {
+		if backend.Name() == name {
+			return backend, nil
+		}
+	}
+	return nil, toStatus(fmt.Errorf("%w: %s", service.ErrBackendNotFound, name), backendMetadata(name))
+}
+
+func (h *MarznodeHandler) restartLock(name string) *sync.Mutex {
+	lock, _ := h.restartLocks.LoadOrStore(name, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
+
+func (h *MarznodeHandler) resolveTag(tag string) (common.VPNBackend, error) {
+	for _, backend := range h.backends {
+		if backend.ContainsTag(tag) {
+			return backend, nil
+		}
+	}
+	return nil, fmt.Errorf("no backend found for %w: %s", service.ErrInboundNotFound, tag)
+}
+
+func usersStatsToProto(cursor string, entries []usage.Entry) *pb.UsersStats {
+	usages := make(map[uint32]uint64)
+	usersTraffic := make([]*pb.UserTraffic, 0, len(entries))
+	for _, entry := range entries {
+		uid := uint32(entry.UID)
+		usages[uid] += uint64(entry.Uplink + entry.Downlink)
+		usersTraffic = append(usersTraffic, &pb.UserTraffic{
+			Uid:        uid,
+			Backend:    entry.Backend,
+			InboundTag: entry.InboundTag,
+			Uplink:     uint64(entry.Uplink),
+			Downlink:   uint64(entry.Downlink),
+		})
+	}
+
+	allUserStats := make([]*pb.UsersStats_UserStats, 0, len(usages))
+	for uid, total := range usages {
+		allUserStats = append(allUserStats, &pb.UsersStats_UserStats{
+			Uid:   uid,
+			Usage: total,
+		})
+	}
 
-	return nil, nil
+	return &pb.UsersStats{
+		UsersStats:   allUserStats,
+		UsersTraffic: usersTraffic,
+		Cursor:       cursor,
+	}
+}
+
+func userFromProto(user *pb.User) models.User {
+	return models.User{
+		ID:       int64(user.GetId()),
+		Username: user.GetUsername(),
+		Key:      user.GetKey(),
+	}
+}
+
+func configFormatToProto(format int) pb.ConfigFormat {
+	switch format {
+	case common.ConfigFormatJSON:
+		return pb.ConfigFormat_JSON
+	case common.ConfigFormatYAML:
+		return pb.ConfigFormat_YAML
+	default:
+		return pb.ConfigFormat_PLAIN
+	}
+}
+
+// tagsDifference returns the tags of a that are not in b. The result is never
+// nil, since a nil tag list means "all inbounds" to ListInbounds.
+func tagsDifference(a, b map[string]struct{}) []string {
+	tags := make([]string, 0, len(a))
+	for tag := range a {
+		if _, ok := b[tag]; !ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func filterInbounds(inbounds []models.Inbound, tags []string) []models.Inbound {
+	var filtered []models.Inbound
+	for _, inbound := range inbounds {
+		for _, tag := range tags {
+			if inbound.Tag == tag {
+				filtered = append(filtered, inbound)
+				break
+			}
+		}
+	}
+	return filtered
 }
@@ -0,0 +1,561 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"marznode/api/pb"
+	"marznode/internal/audit"
+	"marznode/internal/repo"
+	"marznode/internal/service"
+	"marznode/internal/usage"
+	"marznode/pkg/backend/common"
+	"marznode/pkg/backend/common/models"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testBackend keeps the users of its inbounds by key, and like a real core
+// only removes a user given the credentials it was added with.
+type testBackend struct {
+	common.VPNBackend
+	name       string
+	tags       []string
+	users      map[string]map[int64]string
+	failAdd    map[string]bool
+	failRemove map[string]bool
+	ops        *[]string
+	traffic    []common.UserTraffic
+	// requestID is the request ID the last restart was called with.
+	requestID string
+}
+
+func (b *testBackend) Name() string {
+	return b.name
+}
+
+func (b *testBackend) ContainsTag(tag string) bool {
+	return slices.Contains(b.tags, tag)
+}
+
+func (b *testBackend) AddUser(ctx context.Context, user models.User, inbound models.Inbound) error {
+	if b.failAdd[inbound.Tag] {
+		return errors.New("core unavailable")
+	}
+	if b.users[inbound.Tag] == nil {
+		b.users[inbound.Tag] = make(map[int64]string)
+	}
+	b.users[inbound.Tag][user.ID] = user.Key
+	*b.ops = append(*b.ops, fmt.Sprintf("add %d to %s", user.ID, inbound.Tag))
+	return nil
+}
+
+func (b *testBackend) RemoveUser(ctx context.Context, user models.User, inbound models.Inbound) error {
+	if b.failRemove[inbound.Tag] {
+		return errors.New("core unavailable")
+	}
+	if key, ok := b.users[inbound.Tag][user.ID]; !ok || key != user.Key {
+		return fmt.Errorf("user %d with key %s not found", user.ID, user.Key)
+	}
+	delete(b.users[inbound.Tag], user.ID)
+	*b.ops = append(*b.ops, fmt.Sprintf("remove %d from %s", user.ID, inbound.Tag))
+	return nil
+}
+
+// GetUserTraffic returns the traffic set on the backend once, like a core
+// resetting its counters.
+func (b *testBackend) GetUserTraffic(ctx context.Context) ([]common.UserTraffic, error) {
+	traffic := b.traffic
+	b.traffic = nil
+	return traffic, nil
+}
+
+func (b *testBackend) Restart(ctx context.Context, backendConfig any) error {
+	b.requestID = common.RequestID(ctx)
+	return nil
+}
+
+// testStorage records the writes to the in-memory storage.
+type testStorage struct {
+	service.MarznodeMemory
+	ops *[]string
+}
+
+func (s *testStorage) UpdateUserInbounds(ctx context.Context, user models.User, inbounds []models.Inbound) error {
+	*s.ops = append(*s.ops, fmt.Sprintf("store %d", user.ID))
+	return s.MarznodeMemory.UpdateUserInbounds(ctx, user, inbounds)
+}
+
+func (s *testStorage) RemoveUser(ctx context.Context, user models.User) error {
+	*s.ops = append(*s.ops, fmt.Sprintf("delete %d", user.ID))
+	return s.MarznodeMemory.RemoveUser(ctx, user)
+}
+
+type handlerTest struct {
+	handler  *MarznodeHandler
+	storage  *testStorage
+	backends []*testBackend
+	ops      []string
+}
+
+// newHandlerTest creates a handler with two backends, one holding inbounds
+// a and b, the other inbound c.
+func newHandlerTest(t *testing.T) *handlerTest {
+	t.Helper()
+	log := zap.NewNop().Sugar()
+	ht := &handlerTest{}
+	ht.storage = &testStorage{
+		MarznodeMemory: service.NewMarznodeService(repo.NewMarznodeRepository(log), log),
+		ops:            &ht.ops,
+	}
+	ht.backends = []*testBackend{
+		{name: "first", tags: []string{"a", "b"}, users: map[string]map[int64]string{}, ops: &ht.ops},
+		{name: "second", tags: []string{"c"}, users: map[string]map[int64]string{}, ops: &ht.ops},
+	}
+
+	var backends []common.VPNBackend
+	for _, backend := range ht.backends {
+		for _, tag := range backend.tags {
+			if err := ht.storage.RegisterInbound(context.Background(), models.Inbound{Tag: tag}); err != nil {
+				t.Fatalf("failed to register inbound %s: %v", tag, err)
+			}
+		}
+		backends = append(backends, backend)
+	}
+
+	ledger, err := usage.NewLedger("", log)
+	if err != nil {
+		t.Fatalf("failed to create ledger: %v", err)
+	}
+	ht.handler = NewMarznodeHandler(ht.storage, ledger, nil, log, backends...)
+	return ht
+}
+
+// seed puts users on the node as if an earlier sync had added them.
+func (ht *handlerTest) seed(t *testing.T, users ...*pb.UserData) {
+	t.Helper()
+	ctx := context.Background()
+	for _, userData := range users {
+		user := userFromProto(userData.GetUser())
+		var inbounds []models.Inbound
+		for _, inbound := range userData.GetInbounds() {
+			backend, err := ht.handler.resolveTag(inbound.GetTag())
+			if err != nil {
+				t.Fatal(err)
+			}
+			users := backend.(*testBackend).users
+			if users[inbound.GetTag()] == nil {
+				users[inbound.GetTag()] = make(map[int64]string)
+			}
+			users[inbound.GetTag()][user.ID] = user.Key
+			inbounds = append(inbounds, models.Inbound{Tag: inbound.GetTag()})
+		}
+		if err := ht.storage.MarznodeMemory.UpdateUserInbounds(ctx, user, inbounds); err != nil {
+			t.Fatalf("failed to seed user %d: %v", user.ID, err)
+		}
+	}
+}
+
+func (ht *handlerTest) failAdd(tags ...string) {
+	for _, backend := range ht.backends {
+		backend.failAdd = make(map[string]bool)
+		for _, tag := range tags {
+			backend.failAdd[tag] = true
+		}
+	}
+}
+
+func (ht *handlerTest) failRemove(tags ...string) {
+	for _, backend := range ht.backends {
+		backend.failRemove = make(map[string]bool)
+		for _, tag := range tags {
+			backend.failRemove[tag] = true
+		}
+	}
+}
+
+// backendUsers returns the key of every user per inbound of all backends.
+func (ht *handlerTest) backendUsers() map[string]map[int64]string {
+	users := make(map[string]map[int64]string)
+	for _, backend := range ht.backends {
+		for tag, tagUsers := range backend.users {
+			if len(tagUsers) > 0 {
+				users[tag] = tagUsers
+			}
+		}
+	}
+	return users
+}
+
+// storageUsers describes every stored user as "key:tag,tag".
+func (ht *handlerTest) storageUsers(t *testing.T) map[int64]string {
+	t.Helper()
+	users, err := ht.storage.ListUsers(context.Background())
+	if err != nil {
+		t.Fatalf("failed to list users: %v", err)
+	}
+	described := make(map[int64]string)
+	for _, user := range users {
+		var tags []string
+		for _, inbound := range user.Inbounds {
+			tags = append(tags, inbound.Tag)
+		}
+		sort.Strings(tags)
+		described[user.ID] = user.Key + ":" + strings.Join(tags, ",")
+	}
+	return described
+}
+
+func userData(id uint32, key string, tags ...string) *pb.UserData {
+	userData := &pb.UserData{User: &pb.User{Id: id, Username: fmt.Sprintf("user%d", id), Key: key}}
+	for _, tag := range tags {
+		userData.Inbounds = append(userData.Inbounds, &pb.Inbound{Tag: tag})
+	}
+	return userData
+}
+
+func userErrorIDs(errors []*pb.UserError) []uint32 {
+	var ids []uint32
+	for _, userError := range errors {
+		ids = append(ids, userError.GetUid())
+	}
+	return ids
+}
+
+func TestMarznodeHandler_RepopulateUsers(t *testing.T) {
+	tests := []struct {
+		name        string
+		seed        []*pb.UserData
+		failAdd     []string
+		failRemove  []string
+		request     []*pb.UserData
+		added       uint32
+		removed     uint32
+		changed     uint32
+		errors      []uint32
+		wantBackend map[string]map[int64]string
+		wantStorage map[int64]string
+	}{
+		{
+			name:        "adds new users",
+			request:     []*pb.UserData{userData(1, "k1", "a"), userData(2, "k2", "a", "c")},
+			added:       2,
+			wantBackend: map[string]map[int64]string{"a": {1: "k1", 2: "k2"}, "c": {2: "k2"}},
+			wantStorage: map[int64]string{1: "k1:a", 2: "k2:a,c"},
+		},
+		{
+			name:        "moves users between inbounds",
+			seed:        []*pb.UserData{userData(1, "k1", "a", "b")},
+			request:     []*pb.UserData{userData(1, "k1", "b", "c")},
+			changed:     1,
+			wantBackend: map[string]map[int64]string{"b": {1: "k1"}, "c": {1: "k1"}},
+			wantStorage: map[int64]string{1: "k1:b,c"},
+		},
+		{
+			name:        "leaves unchanged users alone",
+			seed:        []*pb.UserData{userData(1, "k1", "a")},
+			request:     []*pb.UserData{userData(1, "k1", "a")},
+			wantBackend: map[string]map[int64]string{"a": {1: "k1"}},
+			wantStorage: map[int64]string{1: "k1:a"},
+		},
+		{
+			name:        "removes users missing from the set",
+			seed:        []*pb.UserData{userData(1, "k1", "a"), userData(2, "k2", "a", "c")},
+			request:     []*pb.UserData{userData(2, "k2", "a", "c")},
+			removed:     1,
+			wantBackend: map[string]map[int64]string{"a": {2: "k2"}, "c": {2: "k2"}},
+			wantStorage: map[int64]string{2: "k2:a,c"},
+		},
+		{
+			name:        "removes users without inbounds",
+			seed:        []*pb.UserData{userData(1, "k1", "a")},
+			request:     []*pb.UserData{userData(1, "k1")},
+			removed:     1,
+			wantBackend: map[string]map[int64]string{},
+			wantStorage: map[int64]string{},
+		},
+		{
+			name:        "re-adds users with changed credentials",
+			seed:        []*pb.UserData{userData(1, "k1", "a")},
+			request:     []*pb.UserData{userData(1, "k2", "a", "c")},
+			changed:     1,
+			wantBackend: map[string]map[int64]string{"a": {1: "k2"}, "c": {1: "k2"}},
+			wantStorage: map[int64]string{1: "k2:a,c"},
+		},
+		{
+			name:        "stores the inbounds a partial failure added",
+			failAdd:     []string{"c"},
+			request:     []*pb.UserData{userData(1, "k1", "a", "c"), userData(2, "k2", "b")},
+			added:       2,
+			errors:      []uint32{1},
+			wantBackend: map[string]map[int64]string{"a": {1: "k1"}, "b": {2: "k2"}},
+			wantStorage: map[int64]string{1: "k1:a", 2: "k2:b"},
+		},
+		{
+			name:        "does not store users no backend accepted",
+			failAdd:     []string{"c"},
+			request:     []*pb.UserData{userData(1, "k1", "c")},
+			errors:      []uint32{1},
+			wantBackend: map[string]map[int64]string{},
+			wantStorage: map[int64]string{},
+		},
+		{
+			name:        "keeps inbounds that failed to remove",
+			seed:        []*pb.UserData{userData(1, "k1", "a", "c")},
+			failRemove:  []string{"c"},
+			request:     []*pb.UserData{userData(1, "k1", "a")},
+			changed:     1,
+			errors:      []uint32{1},
+			wantBackend: map[string]map[int64]string{"a": {1: "k1"}, "c": {1: "k1"}},
+			wantStorage: map[int64]string{1: "k1:a,c"},
+		},
+		{
+			name:        "keeps old credentials when a removal fails",
+			seed:        []*pb.UserData{userData(1, "k1", "a", "c")},
+			failRemove:  []string{"c"},
+			request:     []*pb.UserData{userData(1, "k2", "a", "c")},
+			changed:     1,
+			errors:      []uint32{1},
+			wantBackend: map[string]map[int64]string{"c": {1: "k1"}},
+			wantStorage: map[int64]string{1: "k1:c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ht := newHandlerTest(t)
+			ht.seed(t, tt.seed...)
+			ht.failAdd(tt.failAdd...)
+			ht.failRemove(tt.failRemove...)
+
+			response, err := ht.handler.RepopulateUsers(context.Background(), &pb.UsersData{UsersData: tt.request})
+			if err != nil {
+				t.Fatalf("failed to repopulate users: %v", err)
+			}
+
+			if response.GetAdded() != tt.added || response.GetRemoved() != tt.removed || response.GetChanged() != tt.changed {
+				t.Errorf("expected %d added, %d removed, %d changed, got %d, %d, %d",
+					tt.added, tt.removed, tt.changed, response.GetAdded(), response.GetRemoved(), response.GetChanged())
+			}
+			if got := userErrorIDs(response.GetErrors()); !reflect.DeepEqual(got, tt.errors) {
+				t.Errorf("expected errors for users %v, got %v", tt.errors, got)
+			}
+			if got := ht.backendUsers(); !reflect.DeepEqual(got, tt.wantBackend) {
+				t.Errorf("expected backend users %v, got %v", tt.wantBackend, got)
+			}
+			if got := ht.storageUsers(t); !reflect.DeepEqual(got, tt.wantStorage) {
+				t.Errorf("expected stored users %v, got %v", tt.wantStorage, got)
+			}
+		})
+	}
+}
+
+func TestMarznodeHandler_UpdateUserOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		seed    []*pb.UserData
+		request []*pb.UserData
+		want    []string
+	}{
+		{
+			name:    "add",
+			request: []*pb.UserData{userData(1, "k1", "a")},
+			want:    []string{"add 1 to a", "store 1"},
+		},
+		{
+			name:    "change",
+			seed:    []*pb.UserData{userData(1, "k1", "a")},
+			request: []*pb.UserData{userData(1, "k1", "c")},
+			want:    []string{"remove 1 from a", "add 1 to c", "store 1"},
+		},
+		{
+			name:    "remove",
+			seed:    []*pb.UserData{userData(1, "k1", "a")},
+			request: []*pb.UserData{userData(1, "k1")},
+			want:    []string{"remove 1 from a", "delete 1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ht := newHandlerTest(t)
+			ht.seed(t, tt.seed...)
+
+			if _, err := ht.handler.RepopulateUsers(context.Background(), &pb.UsersData{UsersData: tt.request}); err != nil {
+				t.Fatalf("failed to repopulate users: %v", err)
+			}
+			if !reflect.DeepEqual(ht.ops, tt.want) {
+				t.Errorf("expected backends to be updated before storage %v, got %v", tt.want, ht.ops)
+			}
+		})
+	}
+}
+
+// testSyncStream feeds users to SyncUsers and keeps the response.
+type testSyncStream struct {
+	grpc.ServerStream
+	users    []*pb.UserData
+	err      error
+	response *pb.SyncUsersResponse
+}
+
+func (s *testSyncStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *testSyncStream) Recv() (*pb.UserData, error) {
+	if len(s.users) == 0 {
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+	userData := s.users[0]
+	s.users = s.users[1:]
+	return userData, nil
+}
+
+func (s *testSyncStream) SendAndClose(response *pb.SyncUsersResponse) error {
+	s.response = response
+	return nil
+}
+
+func TestMarznodeHandler_SyncUsers(t *testing.T) {
+	tests := []struct {
+		name        string
+		seed        []*pb.UserData
+		failAdd     []string
+		request     []*pb.UserData
+		errors      []uint32
+		wantBackend map[string]map[int64]string
+		wantStorage map[int64]string
+	}{
+		{
+			name:        "applies every user",
+			seed:        []*pb.UserData{userData(3, "k3", "b")},
+			request:     []*pb.UserData{userData(1, "k1", "a"), userData(2, "k2", "c"), userData(3, "k3")},
+			wantBackend: map[string]map[int64]string{"a": {1: "k1"}, "c": {2: "k2"}},
+			wantStorage: map[int64]string{1: "k1:a", 2: "k2:c"},
+		},
+		{
+			name:        "continues after a failed user",
+			failAdd:     []string{"c"},
+			request:     []*pb.UserData{userData(1, "k1", "c"), userData(2, "k2", "a"), userData(3, "k3", "b", "c")},
+			errors:      []uint32{1, 3},
+			wantBackend: map[string]map[int64]string{"a": {2: "k2"}, "b": {3: "k3"}},
+			wantStorage: map[int64]string{2: "k2:a", 3: "k3:b"},
+		},
+		{
+			name:        "leaves users missing from the stream alone",
+			seed:        []*pb.UserData{userData(1, "k1", "a")},
+			request:     []*pb.UserData{userData(2, "k2", "b")},
+			wantBackend: map[string]map[int64]string{"a": {1: "k1"}, "b": {2: "k2"}},
+			wantStorage: map[int64]string{1: "k1:a", 2: "k2:b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ht := newHandlerTest(t)
+			ht.seed(t, tt.seed...)
+			ht.failAdd(tt.failAdd...)
+
+			stream := &testSyncStream{users: tt.request}
+			if err := ht.handler.SyncUsers(stream); err != nil {
+				t.Fatalf("failed to sync users: %v", err)
+			}
+
+			if stream.response == nil {
+				t.Fatal("expected a response")
+			}
+			if got := userErrorIDs(stream.response.GetErrors()); !reflect.DeepEqual(got, tt.errors) {
+				t.Errorf("expected errors for users %v, got %v", tt.errors, got)
+			}
+			for _, userError := range stream.response.GetErrors() {
+				if !strings.Contains(userError.GetError(), "inbound c") {
+					t.Errorf("expected error of user %d to name the failed inbound, got %q", userError.GetUid(), userError.GetError())
+				}
+			}
+			if got := ht.backendUsers(); !reflect.DeepEqual(got, tt.wantBackend) {
+				t.Errorf("expected backend users %v, got %v", tt.wantBackend, got)
+			}
+			if got := ht.storageUsers(t); !reflect.DeepEqual(got, tt.wantStorage) {
+				t.Errorf("expected stored users %v, got %v", tt.wantStorage, got)
+			}
+		})
+	}
+}
+
+func TestMarznodeHandler_SyncUsersRecvError(t *testing.T) {
+	ht := newHandlerTest(t)
+	stream := &testSyncStream{users: []*pb.UserData{userData(1, "k1", "a")}, err: errors.New("connection reset")}
+
+	if err := ht.handler.SyncUsers(stream); err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Errorf("expected the receive error, got %v", err)
+	}
+	if stream.response != nil {
+		t.Errorf("expected no response after a receive error, got %v", stream.response)
+	}
+	if got := ht.storageUsers(t); !reflect.DeepEqual(got, map[int64]string{1: "k1:a"}) {
+		t.Errorf("expected users received before the error to be applied, got %v", got)
+	}
+}
+
+func TestMarznodeHandler_AckUsageAudited(t *testing.T) {
+	ht := newHandlerTest(t)
+	auditLog, err := audit.New(filepath.Join(t.TempDir(), "audit.log"), 0, 1)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	t.Cleanup(func() { auditLog.Close() })
+	ht.handler.auditLog = auditLog
+	ht.backends[0].traffic = []common.UserTraffic{{UID: 1, Uplink: 10, Downlink: 20}}
+	ctx := context.Background()
+
+	stats, err := ht.handler.FetchUsersStats(ctx, &pb.Empty{})
+	if err != nil {
+		t.Fatalf("failed to fetch stats: %v", err)
+	}
+	if len(stats.GetUsersStats()) != 1 {
+		t.Fatalf("expected the traffic of user 1, got %v", stats.GetUsersStats())
+	}
+	collected, err := ht.handler.CollectUsersStats(ctx, &pb.CollectUsersStatsRequest{})
+	if err != nil {
+		t.Fatalf("failed to collect stats: %v", err)
+	}
+	if len(collected.GetUsersStats()) != 0 {
+		t.Errorf("expected fetched stats to be acknowledged, got %v", collected.GetUsersStats())
+	}
+
+	malformed := "no-sequence"
+	_, err = ht.handler.CollectUsersStats(ctx, &pb.CollectUsersStatsRequest{AckCursor: &malformed})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("expected code %s for a malformed cursor, got %v", codes.InvalidArgument, err)
+	}
+
+	records, err := auditLog.Query(audit.Filter{})
+	if err != nil {
+		t.Fatalf("failed to query audit log: %v", err)
+	}
+	var got []string
+	for _, record := range records {
+		got = append(got, record.RPC+" "+record.UsageCursor+" "+record.Outcome)
+	}
+	want := []string{
+		"FetchUsersStats " + stats.GetCursor() + " OK",
+		"CollectUsersStats " + malformed + " InvalidArgument",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"marznode/api/pb"
+	"marznode/internal/audit"
+	"marznode/internal/service"
+	"marznode/pkg/backend/common"
+	"marznode/pkg/backend/common/models"
+)
+
+// AddInbound adds a single inbound to a backend without restarting it. The
+// tag must not be used by any backend yet.
+func (h *MarznodeHandler) AddInbound(ctx context.Context, request *pb.InboundConfigRequest) (_ *pb.Inbound, err error) {
+	var tag string
+	defer func() {
+		h.audit(ctx, inboundRecord("AddInbound", request.GetBackendName(), tag), err)
+	}()
+
+	backend, err := h.backendByName(request.GetBackendName())
+	if err != nil {
+		return nil, err
+	}
+	tag, err = inboundTag(request.GetConfig())
+	if err != nil {
+		return nil, toStatus(err, backendMetadata(request.GetBackendName()))
+	}
+
+	return h.putInbound(ctx, request, tag, true, backend.AddInbound)
+}
+
+// ReplaceInbound replaces the inbound with the same tag on a backend. Users
+// of the inbound keep it.
+func (h *MarznodeHandler) ReplaceInbound(ctx context.Context, request *pb.InboundConfigRequest) (_ *pb.Inbound, err error) {
+	var tag string
+	defer func() {
+		h.audit(ctx, inboundRecord("ReplaceInbound", request.GetBackendName(), tag), err)
+	}()
+
+	backend, err := h.backendByName(request.GetBackendName())
+	if err != nil {
+		return nil, err
+	}
+	tag, err = inboundTag(request.GetConfig())
+	if err != nil {
+		return nil, toStatus(err, backendMetadata(request.GetBackendName()))
+	}
+
+	return h.putInbound(ctx, request, tag, false, backend.ReplaceInbound)
+}
+
+func (h *MarznodeHandler) RemoveInbound(ctx context.Context, request *pb.RemoveInboundRequest) (_ *pb.Empty, err error) {
+	defer func() {
+		h.audit(ctx, inboundRecord("RemoveInbound", request.GetBackendName(), request.GetTag()), err)
+	}()
+
+	backend, err := h.backendByName(request.GetBackendName())
+	if err != nil {
+		return nil, err
+	}
+
+	h.usersMu.Lock()
+	defer h.usersMu.Unlock()
+	lock := h.restartLock(request.GetBackendName())
+	lock.Lock()
+	defer lock.Unlock()
+
+	h.logger(ctx).Infof("Removing inbound %s from backend %s", request.GetTag(), request.GetBackendName())
+
+	if err := backend.RemoveInbound(ctx, request.GetTag()); err != nil {
+		h.logger(ctx).Errorf("Failed to remove inbound %s from backend %s: %v", request.GetTag(), request.GetBackendName(), err)
+		return nil, toStatus(fmt.Errorf("failed to remove inbound %s: %w", request.GetTag(), err), inboundMetadata(request.GetTag()))
+	}
+
+	return &pb.Empty{}, nil
+}
+
+type putInboundFunc func(ctx context.Context, inboundConfig any) (models.Inbound, error)
+
+// putInbound holds the users lock, so no user changes while its accounts are
+// attached to the inbound, and the backend's restart lock. Every inbound
+// change holds the users lock, so a new tag checked to be unused here can't
+// be taken by another backend before it is added.
+func (h *MarznodeHandler) putInbound(ctx context.Context, request *pb.InboundConfigRequest, tag string, add bool, put putInboundFunc) (*pb.Inbound, error) {
+	h.usersMu.Lock()
+	defer h.usersMu.Unlock()
+	lock := h.restartLock(request.GetBackendName())
+	lock.Lock()
+	defer lock.Unlock()
+
+	if add {
+		if _, err := h.resolveTag(tag); err == nil {
+			return nil, toStatus(fmt.Errorf("%w: %s", service.ErrInboundAlreadyExists, tag), inboundMetadata(tag))
+		}
+	}
+
+	h.logger(ctx).Infof("Updating inbound %s on backend %s", tag, request.GetBackendName())
+
+	inbound, err := put(ctx, request.GetConfig())
+	if err != nil {
+		h.logger(ctx).Errorf("Failed to update inbound %s on backend %s: %v", tag, request.GetBackendName(), err)
+		return nil, toStatus(fmt.Errorf("failed to update inbound %s: %w", tag, err), inboundMetadata(tag))
+	}
+
+	configJSON, err := json.Marshal(inbound.Config)
+	if err != nil {
+		return nil, toStatus(fmt.Errorf("failed to marshal config of inbound %s: %w", tag, err), inboundMetadata(tag))
+	}
+	configStr := string(configJSON)
+
+	return &pb.Inbound{
+		Tag:    inbound.Tag,
+		Config: &configStr,
+	}, nil
+}
+
+func inboundRecord(rpc, backendName, tag string) audit.Record {
+	record := audit.Record{RPC: rpc, Backends: []string{backendName}}
+	if tag != "" {
+		record.Inbounds = []string{tag}
+	}
+	return record
+}
+
+func inboundTag(config string) (string, error) {
+	var inbound struct {
+		Tag string `json:"tag"`
+	}
+	if err := json.Unmarshal([]byte(config), &inbound); err != nil {
+		return "", &common.ConfigError{Stage: common.ConfigStageParse, Messages: []string{err.Error()}}
+	}
+	if inbound.Tag == "" {
+		return "", fmt.Errorf("%w: inbound tag is required", service.ErrInvalidArgument)
+	}
+	return inbound.Tag, nil
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"marznode/internal/grpcutil"
+	"marznode/pkg/backend/common"
+	"runtime/debug"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDKey is the metadata key a request ID is read from and sent back
+// under.
+const RequestIDKey = "x-request-id"
+
+// RequestID returns the ID of the request the context belongs to, or an
+// empty string outside of an RPC.
+func RequestID(ctx context.Context) string {
+	return common.RequestID(ctx)
+}
+
+// WithRequestID stores a request ID in the context, generating one if id is
+// empty. Backends read it from there to tag their logs.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		id = uuid.NewString()
+	}
+	return common.WithRequestID(ctx, id)
+}
+
+// withRequestID takes the request ID from the incoming metadata or generates
+// one, stores it in the context and returns it to the client as a header.
+func withRequestID(ctx context.Context) context.Context {
+	ctx = WithRequestID(ctx, incomingRequestID(ctx))
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIDKey, RequestID(ctx)))
+	return ctx
+}
+
+// incomingRequestID returns the request ID the client sent, if any.
+func incomingRequestID(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(RequestIDKey); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+func RequestIDUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withRequestID(ctx), req)
+	}
+}
+
+func RequestIDStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, grpcutil.WithContext(stream, withRequestID(stream.Context())))
+	}
+}
+
+// LoggingUnaryInterceptor logs every call with its method, duration, status
+// and peer.
+func LoggingUnaryInterceptor(log *zap.SugaredLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(ctx, log, info.FullMethod, start, err)
+		return resp, err
+	}
+}
+
+func LoggingStreamInterceptor(log *zap.SugaredLogger) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, stream)
+		logCall(stream.Context(), log, info.FullMethod, start, err)
+		return err
+	}
+}
+
+func logCall(ctx context.Context, log *zap.SugaredLogger, method string, start time.Time, err error) {
+	code := status.Code(err)
+	fields := []any{
+		"method", method,
+		"duration", time.Since(start),
+		"code", code.String(),
+		"peer", peerAddr(ctx),
+	}
+	if id := RequestID(ctx); id != "" {
+		fields = append(fields, "request_id", id)
+	}
+
+	switch code {
+	case codes.OK:
+		log.Infow("gRPC call", fields...)
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		log.Errorw("gRPC call", append(fields, "error", err)...)
+	default:
+		log.Warnw("gRPC call", append(fields, "error", err)...)
+	}
+}
+
+// RecoveryUnaryInterceptor turns a panic in a handler or another interceptor
+// into an Internal error instead of taking the node down. It has to be the
+// first interceptor of the chain to cover the others.
+func RecoveryUnaryInterceptor(log *zap.SugaredLogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(ctx, log, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func RecoveryStreamInterceptor(log *zap.SugaredLogger) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recovered(stream.Context(), log, info.FullMethod, r)
+			}
+		}()
+		return handler(srv, stream)
+	}
+}
+
+// recovered logs the panic and returns an error that doesn't reveal it to the
+// client. The request ID is only known if the client sent one, as the
+// interceptor runs outside of the one assigning it.
+func recovered(ctx context.Context, log *zap.SugaredLogger, method string, r any) error {
+	log.Errorw("Recovered from panic in gRPC handler",
+		"method", method,
+		"peer", peerAddr(ctx),
+		"request_id", incomingRequestID(ctx),
+		"panic", r,
+		"stack", string(debug.Stack()),
+	)
+	return status.Error(codes.Internal, "internal error")
+}
+
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return "unknown"
+}
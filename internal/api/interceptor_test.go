@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"marznode/api/pb"
+	"marznode/pkg/backend/common"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestRequestIDInterceptors(t *testing.T) {
+	tests := []struct {
+		name string
+		sent string
+	}{
+		{name: "sent by client", sent: "panel-request-1"},
+		{name: "generated"},
+	}
+
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.sent != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(RequestIDKey, tt.sent))
+		}
+		check := func(t *testing.T, handlerCtx context.Context) {
+			t.Helper()
+			id := RequestID(handlerCtx)
+			if tt.sent != "" && id != tt.sent {
+				t.Errorf("expected request ID %q, got %q", tt.sent, id)
+			}
+			if id == "" {
+				t.Error("expected a generated request ID")
+			}
+			if backendID := common.RequestID(handlerCtx); backendID != id {
+				t.Errorf("expected backends to see request ID %q, got %q", id, backendID)
+			}
+		}
+
+		t.Run("unary "+tt.name, func(t *testing.T) {
+			var handlerCtx context.Context
+			_, err := RequestIDUnaryInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/service.MarzService/AddUser"},
+				func(ctx context.Context, req any) (any, error) {
+					handlerCtx = ctx
+					return nil, nil
+				})
+			if err != nil {
+				t.Fatal(err)
+			}
+			check(t, handlerCtx)
+		})
+
+		t.Run("stream "+tt.name, func(t *testing.T) {
+			var handlerCtx context.Context
+			err := RequestIDStreamInterceptor()(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: "/service.MarzService/SyncUsers"},
+				func(srv any, stream grpc.ServerStream) error {
+					handlerCtx = stream.Context()
+					return nil
+				})
+			if err != nil {
+				t.Fatal(err)
+			}
+			check(t, handlerCtx)
+		})
+	}
+}
+
+func TestRequestIDInterceptors_ReachesBackend(t *testing.T) {
+	ht := newHandlerTest(t)
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDKey, "panel-request-1"))
+
+	_, err := RequestIDUnaryInterceptor()(ctx, &pb.RestartBackendRequest{BackendName: "first"},
+		&grpc.UnaryServerInfo{FullMethod: "/service.MarzService/RestartBackend"},
+		func(ctx context.Context, req any) (any, error) {
+			return ht.handler.RestartBackend(ctx, req.(*pb.RestartBackendRequest))
+		})
+	if err != nil {
+		t.Fatalf("failed to restart backend: %v", err)
+	}
+	if id := ht.backends[0].requestID; id != "panel-request-1" {
+		t.Errorf("expected the backend to be called with request ID %q, got %q", "panel-request-1", id)
+	}
+}
+
+func TestRecoveryInterceptors(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(RequestIDKey, "panel-request-1"))
+	check := func(t *testing.T, logs *observer.ObservedLogs, method string, err error) {
+		t.Helper()
+		if code := status.Code(err); code != codes.Internal {
+			t.Fatalf("expected code %s, got %v", codes.Internal, err)
+		}
+		if strings.Contains(err.Error(), "secret state") {
+			t.Errorf("expected the panic value to stay out of the error, got %v", err)
+		}
+
+		entries := logs.All()
+		if len(entries) != 1 {
+			t.Fatalf("expected one log entry, got %d", len(entries))
+		}
+		fields := entries[0].ContextMap()
+		if fields["method"] != method || fields["request_id"] != "panel-request-1" || fields["panic"] != "secret state" {
+			t.Errorf("expected the panic to be logged with method and request ID, got %v", fields)
+		}
+	}
+
+	t.Run("unary", func(t *testing.T) {
+		core, logs := observer.New(zapcore.ErrorLevel)
+		method := "/service.MarzService/AddUser"
+		_, err := RecoveryUnaryInterceptor(zap.New(core).Sugar())(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req any) (any, error) {
+				panic("secret state")
+			})
+		check(t, logs, method, err)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		core, logs := observer.New(zapcore.ErrorLevel)
+		method := "/service.MarzService/SyncUsers"
+		err := RecoveryStreamInterceptor(zap.New(core).Sugar())(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: method},
+			func(srv any, stream grpc.ServerStream) error {
+				panic("secret state")
+			})
+		check(t, logs, method, err)
+	})
+
+	t.Run("no panic", func(t *testing.T) {
+		core, logs := observer.New(zapcore.ErrorLevel)
+		want := status.Error(codes.NotFound, "user not found")
+		_, err := RecoveryUnaryInterceptor(zap.New(core).Sugar())(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/service.MarzService/AddUser"},
+			func(ctx context.Context, req any) (any, error) {
+				return nil, want
+			})
+		if err != want {
+			t.Errorf("expected %v, got %v", want, err)
+		}
+		if logs.Len() != 0 {
+			t.Errorf("expected nothing to be logged, got %d entries", logs.Len())
+		}
+	})
+}
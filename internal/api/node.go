@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"marznode/api/pb"
+	"marznode/internal/sysinfo"
+	"marznode/internal/version"
+)
+
+// GetNodeInfo reports facts about the host the node runs on and the versions
+// of its cores.
+func (h *MarznodeHandler) GetNodeInfo(ctx context.Context, empty *pb.Empty) (*pb.NodeInfo, error) {
+	info, err := sysinfo.Read()
+	if err != nil {
+		return nil, toStatus(fmt.Errorf("failed to read host info: %w", err), nil)
+	}
+
+	interfaces := make([]*pb.NetworkInterface, 0, len(info.Interfaces))
+	for _, netInterface := range info.Interfaces {
+		interfaces = append(interfaces, &pb.NetworkInterface{
+			Name:         netInterface.Name,
+			HardwareAddr: netInterface.HardwareAddr,
+			Addresses:    netInterface.Addresses,
+			Up:           netInterface.Up,
+		})
+	}
+
+	cores := make([]*pb.CoreVersion, 0, len(h.backends))
+	for _, backend := range h.backends {
+		coreVersion, err := backend.Version()
+		if err != nil {
+			h.logger(ctx).Warnf("Failed to get version for backend %s: %v", backend.Name(), err)
+			coreVersion = "unknown"
+		}
+		cores = append(cores, &pb.CoreVersion{
+			Backend: backend.Name(),
+			Type:    backend.BackendType(),
+			Version: coreVersion,
+		})
+	}
+
+	return &pb.NodeInfo{
+		Version:         version.Get(),
+		Hostname:        info.Hostname,
+		Kernel:          info.Kernel,
+		CpuCount:        uint32(info.CPUCount),
+		Load1:           info.Load1,
+		Load5:           info.Load5,
+		Load15:          info.Load15,
+		MemoryTotal:     info.MemoryTotal,
+		MemoryAvailable: info.MemoryAvailable,
+		Uptime:          uint64(info.Uptime.Seconds()),
+		Interfaces:      interfaces,
+		Cores:           cores,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"marznode/api/pb"
+	"marznode/pkg/backend/common/models"
+	"sort"
+)
+
+// GetUser returns the user and inbounds as stored on the node.
+func (h *MarznodeHandler) GetUser(ctx context.Context, request *pb.UserRequest) (*pb.UserData, error) {
+	user, err := h.marznode.GetUser(ctx, int64(request.GetUid()))
+	if err != nil {
+		return nil, toStatus(err, userMetadata(request.GetUid()))
+	}
+
+	inbounds := make([]*pb.Inbound, 0, len(user.Inbounds))
+	for _, inbound := range user.Inbounds {
+		inbounds = append(inbounds, &pb.Inbound{Tag: inbound.Tag})
+	}
+
+	return &pb.UserData{
+		User:     userToProto(*user),
+		Inbounds: inbounds,
+	}, nil
+}
+
+// ListInboundUsers returns the users the node has on an inbound.
+func (h *MarznodeHandler) ListInboundUsers(ctx context.Context, request *pb.InboundRequest) (*pb.InboundUsers, error) {
+	if _, err := h.marznode.GetInbound(ctx, request.GetTag()); err != nil {
+		return nil, toStatus(err, inboundMetadata(request.GetTag()))
+	}
+
+	users, err := h.marznode.ListInboundUsers(ctx, request.GetTag())
+	if err != nil {
+		return nil, toStatus(fmt.Errorf("failed to list users of inbound %s: %w", request.GetTag(), err), inboundMetadata(request.GetTag()))
+	}
+
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	pbUsers := make([]*pb.User, 0, len(users))
+	for _, user := range users {
+		pbUsers = append(pbUsers, userToProto(user))
+	}
+
+	return &pb.InboundUsers{
+		Tag:   request.GetTag(),
+		Users: pbUsers,
+	}, nil
+}
+
+// CountInboundUsers returns the number of users on every registered inbound.
+func (h *MarznodeHandler) CountInboundUsers(ctx context.Context, empty *pb.Empty) (*pb.InboundUsersCounts, error) {
+	inbounds, err := h.marznode.ListInbounds(ctx, nil, false)
+	if err != nil {
+		return nil, toStatus(fmt.Errorf("failed to list inbounds: %w", err), nil)
+	}
+
+	sort.Slice(inbounds, func(i, j int) bool { return inbounds[i].Tag < inbounds[j].Tag })
+
+	counts := make([]*pb.InboundUsersCounts_InboundUsersCount, 0, len(inbounds))
+	for _, inbound := range inbounds {
+		users, err := h.marznode.ListInboundUsers(ctx, inbound.Tag)
+		if err != nil {
+			return nil, toStatus(fmt.Errorf("failed to list users of inbound %s: %w", inbound.Tag, err), inboundMetadata(inbound.Tag))
+		}
+		counts = append(counts, &pb.InboundUsersCounts_InboundUsersCount{
+			Tag:   inbound.Tag,
+			Users: uint32(len(users)),
+		})
+	}
+
+	return &pb.InboundUsersCounts{
+		Counts: counts,
+	}, nil
+}
+
+func userToProto(user models.User) *pb.User {
+	return &pb.User{
+		Id:       uint32(user.ID),
+		Username: user.Username,
+		Key:      user.Key,
+	}
+}
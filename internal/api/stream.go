@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	minStatsInterval   = time.Second
+	minSendTimeout     = 30 * time.Second
+	sendTimeoutPerTick = 3
+)
+
+// slowConsumerTimeout is how long a push may stay blocked on a consumer that
+// does not read before the stream is dropped.
+func slowConsumerTimeout(interval time.Duration) time.Duration {
+	return max(minSendTimeout, sendTimeoutPerTick*interval)
+}
+
+// sendWithTimeout sends msg, giving up on consumers that stop reading. Send
+// unblocks once the handler returns and the stream is torn down.
+func sendWithTimeout[T any](ctx context.Context, stream grpc.ServerStreamingServer[T], msg *T, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- stream.Send(msg)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return status.Errorf(codes.ResourceExhausted, "consumer did not read for %s", timeout)
+	}
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"marznode/api/pb"
+	"runtime"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// blockingStream blocks sends until a message is read from sent or the
+// stream is torn down, like a gRPC stream whose consumer stopped reading.
+type blockingStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *pb.UsersStats
+}
+
+func (s *blockingStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *blockingStream) Send(msg *pb.UsersStats) error {
+	select {
+	case s.sent <- msg:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+func waitForGoroutines(t *testing.T, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected at most %d goroutines, got %d", want, runtime.NumGoroutine())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSendWithTimeout(t *testing.T) {
+	t.Run("sent", func(t *testing.T) {
+		stream := &blockingStream{ctx: context.Background(), sent: make(chan *pb.UsersStats, 1)}
+		msg := &pb.UsersStats{}
+
+		if err := sendWithTimeout(context.Background(), stream, msg, time.Second); err != nil {
+			t.Fatalf("failed to send: %v", err)
+		}
+		if got := <-stream.sent; got != msg {
+			t.Errorf("expected the message to be sent, got %v", got)
+		}
+	})
+
+	t.Run("slow consumer", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+		streamCtx, tearDown := context.WithCancel(context.Background())
+		stream := &blockingStream{ctx: streamCtx, sent: make(chan *pb.UsersStats)}
+
+		start := time.Now()
+		err := sendWithTimeout(context.Background(), stream, &pb.UsersStats{}, 50*time.Millisecond)
+		if code := status.Code(err); code != codes.ResourceExhausted {
+			t.Fatalf("expected code %s, got %v", codes.ResourceExhausted, err)
+		}
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Errorf("expected to wait for the timeout, returned after %s", elapsed)
+		}
+
+		// The blocked send ends with the stream once the handler returns.
+		tearDown()
+		waitForGoroutines(t, before)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+		streamCtx, tearDown := context.WithCancel(context.Background())
+		stream := &blockingStream{ctx: streamCtx, sent: make(chan *pb.UsersStats)}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := sendWithTimeout(ctx, stream, &pb.UsersStats{}, time.Minute)
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+
+		tearDown()
+		waitForGoroutines(t, before)
+	})
+}
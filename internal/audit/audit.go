@@ -0,0 +1,254 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrDisabled is returned when querying a node without an audit log.
+var ErrDisabled = errors.New("audit log is disabled")
+
+// Record is a state-changing call. Outcome is the gRPC code of the call.
+type Record struct {
+	Time      time.Time `json:"time"`
+	Caller    string    `json:"caller"`
+	Peer      string    `json:"peer,omitempty"`
+	RPC       string    `json:"rpc"`
+	RequestID string    `json:"request_id,omitempty"`
+	Users     []uint32  `json:"users,omitempty"`
+	Backends  []string  `json:"backends,omitempty"`
+	Inbounds  []string  `json:"inbounds,omitempty"`
+	// UsageCursor is the usage cursor a call acknowledged.
+	UsageCursor string `json:"usage_cursor,omitempty"`
+	Outcome     string `json:"outcome"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Filter selects records. Zero fields match every record. Limit keeps the
+// most recent records.
+type Filter struct {
+	Since   time.Time
+	Until   time.Time
+	RPC     string
+	UID     *uint32
+	Backend string
+	Limit   int
+}
+
+func (f Filter) match(record Record) bool {
+	switch {
+	case !f.Since.IsZero() && record.Time.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && record.Time.After(f.Until):
+		return false
+	case f.RPC != "" && record.RPC != f.RPC:
+		return false
+	case f.UID != nil && !slices.Contains(record.Users, *f.UID):
+		return false
+	case f.Backend != "" && !slices.Contains(record.Backends, f.Backend):
+		return false
+	}
+	return true
+}
+
+// Log appends records to a JSON-lines file. Once the file would grow past
+// maxSize it is rotated to path.1, path.1 to path.2 and so on, keeping
+// maxBackups old files. A nil Log drops records.
+//
+// mu serializes writers. Queries only wait for rotations, which hold
+// rotateMu while renaming the files.
+type Log struct {
+	mu         sync.Mutex
+	rotateMu   sync.RWMutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// New opens the audit log at path. It returns a nil Log if path is empty.
+func New(path string, maxSize int64, maxBackups int) (*Log, error) {
+	if path == "" {
+		return nil, nil
+	}
+	if maxBackups < 1 {
+		return nil, errors.Errorf("audit log %s must keep at least one backup, got %d", path, maxBackups)
+	}
+
+	l := &Log{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Log) Write(record Record) error {
+	if l == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "error encoding audit record")
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var rotateErr error
+	if l.file != nil && l.maxSize > 0 && l.size > 0 && l.size+int64(len(data)) > l.maxSize {
+		rotateErr = l.rotate()
+	}
+	// A failed rotation or reopen leaves no file; the record still goes to
+	// the log if it can be opened again.
+	if l.file == nil {
+		if err := l.open(); err != nil {
+			return withRotateError(err, rotateErr)
+		}
+	}
+
+	n, err := l.file.Write(data)
+	l.size += int64(n)
+	if err != nil {
+		return withRotateError(errors.Wrapf(err, "error writing audit log %s", l.path), rotateErr)
+	}
+	return rotateErr
+}
+
+// withRotateError notes a failed rotation on err, which caused the write to
+// fail as well.
+func withRotateError(err, rotateErr error) error {
+	if rotateErr == nil {
+		return err
+	}
+	return errors.Wrapf(err, "after %v", rotateErr)
+}
+
+// Query returns the matching records of the log and its backups, oldest
+// first.
+func (l *Log) Query(filter Filter) ([]Record, error) {
+	if l == nil {
+		return nil, ErrDisabled
+	}
+
+	l.rotateMu.RLock()
+	defer l.rotateMu.RUnlock()
+
+	var records []Record
+	for i := l.maxBackups; i >= 0; i-- {
+		fileRecords, err := readRecords(l.backupPath(i), filter)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, fileRecords...)
+	}
+
+	if filter.Limit > 0 && len(records) > filter.Limit {
+		records = records[len(records)-filter.Limit:]
+	}
+	return records, nil
+}
+
+func (l *Log) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+func (l *Log) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "error opening audit log %s", l.path)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return errors.Wrapf(err, "error reading audit log %s", l.path)
+	}
+
+	l.file = file
+	l.size = info.Size()
+	return nil
+}
+
+// rotate closes the log and shifts the backups. It leaves l.file nil if it
+// fails before the new log is open.
+func (l *Log) rotate() error {
+	l.rotateMu.Lock()
+	defer l.rotateMu.Unlock()
+
+	err := l.file.Close()
+	l.file = nil
+	if err != nil {
+		return errors.Wrapf(err, "error closing audit log %s", l.path)
+	}
+
+	for i := l.maxBackups - 1; i >= 0; i-- {
+		err := os.Rename(l.backupPath(i), l.backupPath(i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "error rotating audit log %s", l.path)
+		}
+	}
+	return l.open()
+}
+
+// backupPath returns the path of the nth backup, the log itself for 0.
+func (l *Log) backupPath(n int) string {
+	if n == 0 {
+		return l.path
+	}
+	return fmt.Sprintf("%s.%d", l.path, n)
+}
+
+func readRecords(path string, filter Filter) ([]Record, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening audit log %s", path)
+	}
+	defer file.Close()
+
+	// Records of large syncs can outgrow the line limit of bufio.Scanner.
+	reader := bufio.NewReader(file)
+	var records []Record
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var record Record
+			// A line cut short by a crash is skipped.
+			if json.Unmarshal(line, &record) == nil && filter.match(record) {
+				records = append(records, record)
+			}
+		}
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading audit log %s", path)
+		}
+	}
+}
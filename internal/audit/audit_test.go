@@ -0,0 +1,171 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func newTestLog(t *testing.T, maxSize int64, maxBackups int) *Log {
+	t.Helper()
+	l, err := New(filepath.Join(t.TempDir(), "audit.log"), maxSize, maxBackups)
+	if err != nil {
+		t.Fatalf("failed to open audit log: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+func writeRecords(t *testing.T, l *Log, records ...Record) {
+	t.Helper()
+	for _, record := range records {
+		if err := l.Write(record); err != nil {
+			t.Fatalf("failed to write record: %v", err)
+		}
+	}
+}
+
+func rpcs(records []Record) []string {
+	var names []string
+	for _, record := range records {
+		names = append(names, record.RPC)
+	}
+	return names
+}
+
+func TestNew(t *testing.T) {
+	l, err := New("", 0, 0)
+	if l != nil || err != nil {
+		t.Errorf("expected a nil log for an empty path, got %v, %v", l, err)
+	}
+	if err := l.Write(Record{RPC: "AddUser"}); err != nil {
+		t.Errorf("expected a nil log to drop records, got %v", err)
+	}
+	if _, err := l.Query(Filter{}); err != ErrDisabled {
+		t.Errorf("expected ErrDisabled, got %v", err)
+	}
+
+	if _, err := New(filepath.Join(t.TempDir(), "audit.log"), 1024, 0); err == nil {
+		t.Error("expected an error for a log without backups")
+	}
+}
+
+func TestLog_Query(t *testing.T) {
+	l := newTestLog(t, 0, 1)
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	writeRecords(t, l,
+		Record{Time: start, RPC: "AddUser", Users: []uint32{1}, Backends: []string{"sing-box"}, Outcome: "OK"},
+		Record{Time: start.Add(time.Minute), RPC: "RemoveUser", Users: []uint32{2}, Backends: []string{"sing-box"}, Outcome: "OK"},
+		Record{Time: start.Add(2 * time.Minute), RPC: "RestartBackend", Backends: []string{"xray"}, Outcome: "Internal", Error: "boom"},
+		Record{Time: start.Add(3 * time.Minute), RPC: "AckUsersStats", UsageCursor: "abc-1", Outcome: "OK"},
+	)
+
+	uid := uint32(2)
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{name: "all", filter: Filter{}, want: []string{"AddUser", "RemoveUser", "RestartBackend", "AckUsersStats"}},
+		{name: "since", filter: Filter{Since: start.Add(2 * time.Minute)}, want: []string{"RestartBackend", "AckUsersStats"}},
+		{name: "until", filter: Filter{Until: start.Add(time.Minute)}, want: []string{"AddUser", "RemoveUser"}},
+		{name: "rpc", filter: Filter{RPC: "RestartBackend"}, want: []string{"RestartBackend"}},
+		{name: "uid", filter: Filter{UID: &uid}, want: []string{"RemoveUser"}},
+		{name: "backend", filter: Filter{Backend: "sing-box"}, want: []string{"AddUser", "RemoveUser"}},
+		{name: "limit keeps latest", filter: Filter{Limit: 2}, want: []string{"RestartBackend", "AckUsersStats"}},
+		{name: "no match", filter: Filter{RPC: "AddInbound"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, err := l.Query(tt.filter)
+			if err != nil {
+				t.Fatalf("failed to query: %v", err)
+			}
+			if got := rpcs(records); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	records, err := l.Query(Filter{RPC: "AckUsersStats"})
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	if len(records) != 1 || records[0].UsageCursor != "abc-1" {
+		t.Errorf("expected the acknowledged cursor to be kept, got %v", records)
+	}
+}
+
+func TestLog_SkipsTruncatedLines(t *testing.T) {
+	l := newTestLog(t, 0, 1)
+	writeRecords(t, l, Record{RPC: "AddUser"})
+	if _, err := l.file.WriteString(`{"rpc":"Remove`); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := l.Query(Filter{})
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	if got := rpcs(records); !reflect.DeepEqual(got, []string{"AddUser"}) {
+		t.Errorf("expected the truncated line to be skipped, got %v", got)
+	}
+}
+
+func TestLog_Rotate(t *testing.T) {
+	// Every record outgrows maxSize, so each write after the first rotates.
+	l := newTestLog(t, 1, 2)
+	writeRecords(t, l,
+		Record{RPC: "first"},
+		Record{RPC: "second"},
+		Record{RPC: "third"},
+		Record{RPC: "fourth"},
+	)
+
+	for i := 0; i <= 2; i++ {
+		if _, err := os.Stat(l.backupPath(i)); err != nil {
+			t.Errorf("expected %s to exist: %v", l.backupPath(i), err)
+		}
+	}
+	if _, err := os.Stat(l.backupPath(3)); !os.IsNotExist(err) {
+		t.Errorf("expected only 2 backups, got %s", l.backupPath(3))
+	}
+
+	records, err := l.Query(Filter{})
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	want := []string{"second", "third", "fourth"}
+	if got := rpcs(records); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func TestLog_RotateFailure(t *testing.T) {
+	l := newTestLog(t, 1, 2)
+	writeRecords(t, l, Record{RPC: "first"}, Record{RPC: "second"})
+
+	// A non-empty directory in place of the second backup fails the rename.
+	if err := os.MkdirAll(filepath.Join(l.backupPath(2), "blocker"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Write(Record{RPC: "third"}); err == nil {
+		t.Fatal("expected the failed rotation to be reported")
+	}
+	if err := os.RemoveAll(l.backupPath(2)); err != nil {
+		t.Fatal(err)
+	}
+	writeRecords(t, l, Record{RPC: "fourth"})
+
+	records, err := l.Query(Filter{})
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	want := []string{"first", "second", "third", "fourth"}
+	if got := rpcs(records); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected records to survive a failed rotation %v, got %v", want, got)
+	}
+}
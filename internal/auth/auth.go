@@ -0,0 +1,268 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"marznode/internal/config"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Modes of client authentication.
+const (
+	ModeMTLS  = "mtls"
+	ModeToken = "token"
+)
+
+const minTokenLength = 16
+
+var ErrInvalidToken = errors.New("invalid token")
+
+type subjectContextKey struct{}
+
+// Subject returns the authenticated token the context belongs to, or an
+// empty string if the client was not authenticated by token.
+func Subject(ctx context.Context) string {
+	subject, _ := ctx.Value(subjectContextKey{}).(string)
+	return subject
+}
+
+func WithSubject(ctx context.Context, subject string) context.Context {
+	return context.WithValue(ctx, subjectContextKey{}, subject)
+}
+
+// Authenticator checks bearer tokens. A token is either one of the secrets
+// itself, or one signed with a secret by SignToken. Secrets come from the
+// config and the token file, one per line. A secret dropped from the file
+// keeps working for the grace period, so panels can switch to the new one.
+// The config token is rotated by restarting with the old one as the
+// previous token, which is retired on startup.
+type Authenticator struct {
+	mu      sync.RWMutex
+	secrets []string
+	// retired maps secrets dropped on rotation to the end of their grace
+	// period.
+	retired map[string]time.Time
+
+	token       string
+	tokenFile   string
+	gracePeriod time.Duration
+	maxAge      time.Duration
+	log         *zap.SugaredLogger
+}
+
+func New(cfg config.Auth, log *zap.SugaredLogger) (*Authenticator, error) {
+	a := &Authenticator{
+		retired:     make(map[string]time.Time),
+		token:       cfg.Token,
+		tokenFile:   cfg.TokenFile,
+		gracePeriod: cfg.GracePeriod,
+		maxAge:      cfg.SignedTokenMaxAge,
+		log:         log,
+	}
+
+	secrets, err := a.loadSecrets()
+	if err != nil {
+		return nil, err
+	}
+	if len(secrets) == 0 {
+		return nil, errors.New("a token or token file is required in token auth mode")
+	}
+	a.secrets = secrets
+
+	if previous := cfg.PreviousToken; previous != "" && !slices.Contains(secrets, previous) {
+		if len(previous) < minTokenLength {
+			return nil, errors.Errorf("previous token %s is shorter than %d characters", fingerprint(previous), minTokenLength)
+		}
+		a.retired[previous] = time.Now().Add(a.gracePeriod)
+		log.Infow("Auth token retired", "token", fingerprint(previous), "grace_period", a.gracePeriod)
+	}
+	return a, nil
+}
+
+// Run reloads the token file every interval until the context is done.
+func (a *Authenticator) Run(ctx context.Context, interval time.Duration) {
+	if a.tokenFile == "" {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := a.Reload(); err != nil {
+				a.log.Errorw("Failed to reload auth tokens", "error", err)
+			}
+		}
+	}
+}
+
+// Reload reads the token file again. Secrets no longer present are retired
+// for the grace period.
+func (a *Authenticator) Reload() error {
+	secrets, err := a.loadSecrets()
+	if err != nil {
+		return err
+	}
+	if len(secrets) == 0 {
+		return errors.New("no tokens left after reload, keeping the current ones")
+	}
+	a.setSecrets(secrets, time.Now())
+	return nil
+}
+
+func (a *Authenticator) setSecrets(secrets []string, now time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.deleteExpired(now)
+	for _, secret := range a.secrets {
+		if !slices.Contains(secrets, secret) {
+			a.retired[secret] = now.Add(a.gracePeriod)
+			a.log.Infow("Auth token retired", "token", fingerprint(secret), "grace_period", a.gracePeriod)
+		}
+	}
+	for _, secret := range secrets {
+		if _, ok := a.retired[secret]; ok {
+			delete(a.retired, secret)
+		} else if !slices.Contains(a.secrets, secret) {
+			a.log.Infow("Auth token added", "token", fingerprint(secret))
+		}
+	}
+	a.secrets = secrets
+}
+
+// Authenticate checks a token and returns the subject it authenticates as,
+// naming the secret by its fingerprint.
+func (a *Authenticator) Authenticate(token string) (string, error) {
+	return a.authenticate(token, time.Now())
+}
+
+func (a *Authenticator) authenticate(token string, now time.Time) (string, error) {
+	if token == "" {
+		return "", errors.Wrap(ErrInvalidToken, "missing token")
+	}
+
+	secrets := a.validSecrets(now)
+	for _, secret := range secrets {
+		if hmac.Equal([]byte(token), []byte(secret)) {
+			return "token:" + fingerprint(secret), nil
+		}
+	}
+
+	expiresStr, signature, found := strings.Cut(token, ".")
+	if !found {
+		return "", ErrInvalidToken
+	}
+	expires, err := strconv.ParseInt(expiresStr, 10, 64)
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	switch {
+	case now.Unix() > expires:
+		return "", errors.Wrap(ErrInvalidToken, "token expired")
+	case time.Unix(expires, 0).Sub(now) > a.maxAge:
+		return "", errors.Wrap(ErrInvalidToken, "token lifetime exceeds the maximum")
+	}
+
+	for _, secret := range secrets {
+		if hmac.Equal([]byte(signature), []byte(sign(secret, expiresStr))) {
+			return "signed:" + fingerprint(secret), nil
+		}
+	}
+	return "", ErrInvalidToken
+}
+
+func (a *Authenticator) validSecrets(now time.Time) []string {
+	a.mu.RLock()
+	secrets := slices.Clone(a.secrets)
+	expired := false
+	for secret, expires := range a.retired {
+		if now.Before(expires) {
+			secrets = append(secrets, secret)
+		} else {
+			expired = true
+		}
+	}
+	a.mu.RUnlock()
+
+	if expired {
+		a.mu.Lock()
+		a.deleteExpired(now)
+		a.mu.Unlock()
+	}
+	return secrets
+}
+
+// deleteExpired drops retired secrets past their grace period. It must be
+// called with mu held.
+func (a *Authenticator) deleteExpired(now time.Time) {
+	for secret, expires := range a.retired {
+		if !now.Before(expires) {
+			delete(a.retired, secret)
+			a.log.Infow("Auth token grace period ended", "token", fingerprint(secret))
+		}
+	}
+}
+
+func (a *Authenticator) loadSecrets() ([]string, error) {
+	var secrets []string
+	if a.token != "" {
+		secrets = append(secrets, a.token)
+	}
+
+	if a.tokenFile != "" {
+		data, err := os.ReadFile(a.tokenFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading token file %s", a.tokenFile)
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" || strings.HasPrefix(line, "#") || slices.Contains(secrets, line) {
+				continue
+			}
+			secrets = append(secrets, line)
+		}
+	}
+
+	for _, secret := range secrets {
+		if len(secret) < minTokenLength {
+			return nil, errors.Errorf("token %s is shorter than %d characters", fingerprint(secret), minTokenLength)
+		}
+	}
+	return secrets, nil
+}
+
+// SignToken creates a token valid until expires. The node rejects tokens
+// that expire further ahead than the configured maximum age.
+func SignToken(secret string, expires time.Time) string {
+	expiresStr := strconv.FormatInt(expires.Unix(), 10)
+	return expiresStr + "." + sign(secret, expiresStr)
+}
+
+func sign(secret, expires string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("marznode." + expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// fingerprint identifies a secret in logs and audit records without
+// revealing it.
+func fingerprint(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:4])
+}
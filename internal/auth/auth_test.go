@@ -0,0 +1,251 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"marznode/internal/config"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testSecret      = "current-secret-0123456789"
+	testOtherSecret = "retired-secret-0123456789"
+)
+
+func newTestAuthenticator(t *testing.T, cfg config.Auth) *Authenticator {
+	t.Helper()
+	if cfg.GracePeriod == 0 {
+		cfg.GracePeriod = time.Hour
+	}
+	if cfg.SignedTokenMaxAge == 0 {
+		cfg.SignedTokenMaxAge = 5 * time.Minute
+	}
+	a, err := New(cfg, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+	return a
+}
+
+func tamper(token string) string {
+	last := token[len(token)-1]
+	if last == 'A' {
+		return token[:len(token)-1] + "B"
+	}
+	return token[:len(token)-1] + "A"
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.Auth
+	}{
+		{name: "no secrets", cfg: config.Auth{}},
+		{name: "short token", cfg: config.Auth{Token: "short"}},
+		{name: "short previous token", cfg: config.Auth{Token: testSecret, PreviousToken: "short"}},
+		{name: "missing token file", cfg: config.Auth{TokenFile: filepath.Join(t.TempDir(), "missing")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(tt.cfg, zap.NewNop().Sugar()); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	a := newTestAuthenticator(t, config.Auth{Token: testSecret})
+	now := time.Now()
+	_, signature, _ := strings.Cut(SignToken(testSecret, now.Add(time.Minute)), ".")
+	extendedToken := strconv.FormatInt(now.Add(2*time.Minute).Unix(), 10) + "." + signature
+
+	tests := []struct {
+		name    string
+		token   string
+		subject string
+	}{
+		{name: "secret", token: testSecret, subject: "token:" + fingerprint(testSecret)},
+		{name: "signed", token: SignToken(testSecret, now.Add(time.Minute)), subject: "signed:" + fingerprint(testSecret)},
+		{name: "signed at max age", token: SignToken(testSecret, now.Add(5*time.Minute)), subject: "signed:" + fingerprint(testSecret)},
+		{name: "missing"},
+		{name: "unknown secret", token: testOtherSecret},
+		{name: "expired", token: SignToken(testSecret, now.Add(-time.Second))},
+		{name: "over max age", token: SignToken(testSecret, now.Add(10*time.Minute))},
+		{name: "tampered signature", token: tamper(SignToken(testSecret, now.Add(time.Minute)))},
+		{name: "tampered expiry", token: extendedToken},
+		{name: "signed by unknown secret", token: SignToken(testOtherSecret, now.Add(time.Minute))},
+		{name: "malformed expiry", token: "soon." + sign(testSecret, "soon")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject, err := a.authenticate(tt.token, now)
+			if tt.subject == "" {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Errorf("expected ErrInvalidToken, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected token to be accepted, got %v", err)
+			}
+			if subject != tt.subject {
+				t.Errorf("expected subject %s, got %s", tt.subject, subject)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_Rotation(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "tokens")
+	if err := os.WriteFile(tokenFile, []byte("# panel tokens\n"+testOtherSecret+"\n"+testSecret+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	a := newTestAuthenticator(t, config.Auth{TokenFile: tokenFile})
+
+	if err := os.WriteFile(tokenFile, []byte(testSecret+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Reload(); err != nil {
+		t.Fatalf("failed to reload: %v", err)
+	}
+	rotated := time.Now()
+
+	tests := []struct {
+		name  string
+		token string
+		after time.Duration
+		valid bool
+	}{
+		{name: "retired secret in grace period", token: testOtherSecret, after: 30 * time.Minute, valid: true},
+		{name: "retired signed token in grace period", token: SignToken(testOtherSecret, rotated.Add(31*time.Minute)), after: 30 * time.Minute, valid: true},
+		{name: "retired secret after grace period", token: testOtherSecret, after: 2 * time.Hour},
+		{name: "retired signed token after grace period", token: SignToken(testOtherSecret, rotated.Add(121*time.Minute)), after: 2 * time.Hour},
+		{name: "current secret", token: testSecret, after: 2 * time.Hour, valid: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := a.authenticate(tt.token, rotated.Add(tt.after))
+			if tt.valid && err != nil {
+				t.Errorf("expected token to be accepted, got %v", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("expected ErrInvalidToken, got %v", err)
+			}
+		})
+	}
+
+	if len(a.retired) != 0 {
+		t.Errorf("expected the expired secret to be deleted, got %d retired secrets", len(a.retired))
+	}
+
+	if err := os.WriteFile(tokenFile, []byte("# no tokens\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Reload(); err == nil {
+		t.Error("expected reload without tokens to fail")
+	}
+	if _, err := a.Authenticate(testSecret); err != nil {
+		t.Errorf("expected a failed reload to keep the current secrets, got %v", err)
+	}
+}
+
+func TestAuthenticator_PreviousToken(t *testing.T) {
+	a := newTestAuthenticator(t, config.Auth{Token: testSecret, PreviousToken: testOtherSecret})
+	started := time.Now()
+
+	if _, err := a.authenticate(testOtherSecret, started.Add(30*time.Minute)); err != nil {
+		t.Errorf("expected the previous token to be accepted in the grace period, got %v", err)
+	}
+	if _, err := a.authenticate(testOtherSecret, started.Add(2*time.Hour)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("expected the previous token to be rejected after the grace period, got %v", err)
+	}
+	if _, err := a.authenticate(testSecret, started.Add(2*time.Hour)); err != nil {
+		t.Errorf("expected the current token to be accepted, got %v", err)
+	}
+}
+
+type testServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *testServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestAuthenticator_Interceptors(t *testing.T) {
+	a := newTestAuthenticator(t, config.Auth{Token: testSecret})
+
+	tests := []struct {
+		name    string
+		method  string
+		header  string
+		code    codes.Code
+		subject string
+	}{
+		{name: "valid token", method: "/service.MarzService/AddUser", header: "Bearer " + testSecret, code: codes.OK, subject: "token:" + fingerprint(testSecret)},
+		{name: "lowercase scheme", method: "/service.MarzService/AddUser", header: "bearer " + testSecret, code: codes.OK, subject: "token:" + fingerprint(testSecret)},
+		{name: "invalid token", method: "/service.MarzService/AddUser", header: "Bearer " + testOtherSecret, code: codes.Unauthenticated},
+		{name: "wrong scheme", method: "/service.MarzService/AddUser", header: "Basic " + testSecret, code: codes.Unauthenticated},
+		{name: "missing header", method: "/service.MarzService/AddUser", code: codes.Unauthenticated},
+		{name: "health check", method: "/grpc.health.v1.Health/Check", code: codes.OK},
+	}
+
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.header != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.header))
+		}
+		check := func(t *testing.T, handlerCtx context.Context, called bool, err error) {
+			t.Helper()
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("expected code %s, got %s", tt.code, code)
+			}
+			if called != (tt.code == codes.OK) {
+				t.Fatalf("expected handler to be called: %v, got %v", tt.code == codes.OK, called)
+			}
+			if called {
+				if subject := Subject(handlerCtx); subject != tt.subject {
+					t.Errorf("expected subject %q, got %q", tt.subject, subject)
+				}
+			}
+		}
+
+		t.Run("unary "+tt.name, func(t *testing.T) {
+			var handlerCtx context.Context
+			called := false
+			_, err := a.UnaryInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method},
+				func(ctx context.Context, req any) (any, error) {
+					handlerCtx, called = ctx, true
+					return nil, nil
+				})
+			check(t, handlerCtx, called, err)
+		})
+
+		t.Run("stream "+tt.name, func(t *testing.T) {
+			var handlerCtx context.Context
+			called := false
+			err := a.StreamInterceptor()(nil, &testServerStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: tt.method},
+				func(srv any, stream grpc.ServerStream) error {
+					handlerCtx, called = stream.Context(), true
+					return nil
+				})
+			check(t, handlerCtx, called, err)
+		})
+	}
+}
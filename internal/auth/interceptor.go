@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"context"
+	"marznode/internal/grpcutil"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// healthServicePrefix is left open, so load balancers and probes can check
+// the node without a token.
+const healthServicePrefix = "/grpc.health.v1.Health/"
+
+func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(ctx, req)
+		}
+		ctx, err := a.authenticateContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if strings.HasPrefix(info.FullMethod, healthServicePrefix) {
+			return handler(srv, stream)
+		}
+		ctx, err := a.authenticateContext(stream.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, grpcutil.WithContext(stream, ctx))
+	}
+}
+
+// AuthenticateHeader checks the value of an authorization header and returns
+// the context with the subject it authenticates as.
+func (a *Authenticator) AuthenticateHeader(ctx context.Context, header string) (context.Context, error) {
+	token, ok := bearerToken(header)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	subject, err := a.Authenticate(token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return WithSubject(ctx, subject), nil
+}
+
+func (a *Authenticator) authenticateContext(ctx context.Context) (context.Context, error) {
+	var header string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+	}
+	return a.AuthenticateHeader(ctx, header)
+}
+
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
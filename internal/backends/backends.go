@@ -0,0 +1,64 @@
+package backends
+
+import (
+	"context"
+	"marznode/internal/config"
+	"marznode/internal/service"
+	"marznode/pkg/backend/common"
+	"marznode/pkg/backend/singbox"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// New builds the backend described by cfg without starting it.
+func New(cfg config.Backend, storage *service.BackendStorage, log *zap.SugaredLogger) (common.VPNBackend, error) {
+	logger := log.With("backend", cfg.Name)
+
+	switch cfg.Type {
+	case config.BackendTypeSingBox:
+		backend, err := singbox.NewSingBoxBackend(cfg.Name, cfg.ExecutablePath, cfg.AssetsPath, cfg.ConfigPath, storage, logger)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating sing-box backend")
+		}
+		return backend, nil
+	default:
+		return nil, errors.Errorf("backend type %s is not supported", cfg.Type)
+	}
+}
+
+// StartAll builds and starts every enabled backend. Backends that fail to
+// start are still returned so they can be fixed with RestartBackend.
+func StartAll(ctx context.Context, cfgs []config.Backend, storage *service.BackendStorage, log *zap.SugaredLogger) ([]common.VPNBackend, error) {
+	var backends []common.VPNBackend
+
+	for _, cfg := range cfgs {
+		if !cfg.Enabled {
+			log.Infof("Backend %s is disabled, skipping", cfg.Name)
+			continue
+		}
+
+		backend, err := New(cfg, storage, log)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := backend.Start(ctx, nil); err != nil {
+			log.Errorf("Failed to start backend %s: %v", cfg.Name, err)
+		} else {
+			log.Infof("Started backend %s", cfg.Name)
+		}
+
+		backends = append(backends, backend)
+	}
+
+	return backends, nil
+}
+
+func StopAll(ctx context.Context, backends []common.VPNBackend, log *zap.SugaredLogger) {
+	for _, backend := range backends {
+		if err := backend.Stop(ctx); err != nil {
+			log.Errorf("Failed to stop backend %s: %v", backend.Name(), err)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"marznode/internal/config"
+)
+
+const certValidity = 10 * 365 * 24 * time.Hour
+
+// ServerTLSConfig loads the node certificate, generating it on first boot.
+// With requireClientCert, clients must present a certificate signed by the
+// panel CA.
+func ServerTLSConfig(cfg config.Grpc, requireClientCert bool) (*tls.Config, error) {
+	if requireClientCert && cfg.ClientCertFile == "" {
+		return nil, errors.New("client certificate is required unless insecure mode or token auth is enabled")
+	}
+
+	if err := ensureCertificate(cfg.CertFile, cfg.KeyFile); err != nil {
+		return nil, err
+	}
+
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "error loading node certificate")
+	}
+
+	if !requireClientCert {
+		return &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}, nil
+	}
+
+	clientCA, err := os.ReadFile(cfg.ClientCertFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading client certificate")
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(clientCA) {
+		return nil, errors.Errorf("no certificates found in %s", cfg.ClientCertFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// ensureCertificate generates the node certificate and key if neither
+// exists. If only one of them does, it was supplied by the operator and is
+// left alone.
+func ensureCertificate(certFile, keyFile string) error {
+	_, certErr := os.Stat(certFile)
+	_, keyErr := os.Stat(keyFile)
+	switch {
+	case certErr == nil && keyErr == nil:
+		return nil
+	case certErr != nil && !os.IsNotExist(certErr):
+		return errors.Wrap(certErr, "error checking node certificate")
+	case keyErr != nil && !os.IsNotExist(keyErr):
+		return errors.Wrap(keyErr, "error checking node key")
+	case certErr == nil:
+		return errors.Errorf("node certificate %s exists but its key %s is missing", certFile, keyFile)
+	case keyErr == nil:
+		return errors.Errorf("node key %s exists but its certificate %s is missing", keyFile, certFile)
+	}
+
+	certPEM, keyPEM, err := generateCertificate()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range []string{certFile, keyFile} {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return errors.Wrapf(err, "error creating directory for %s", path)
+		}
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		return errors.Wrap(err, "error writing node key")
+	}
+	if err := os.WriteFile(certFile, certPEM, 0644); err != nil {
+		return errors.Wrap(err, "error writing node certificate")
+	}
+
+	return nil
+}
+
+func generateCertificate() ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error generating key")
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error generating serial number")
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "marznode"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(certValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error creating certificate")
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error marshalling key")
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	return certPEM, keyPEM, nil
+}
@@ -0,0 +1,127 @@
+package certs
+
+import (
+	"bytes"
+	"crypto/tls"
+	"marznode/internal/config"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func testPaths(t *testing.T) (string, string) {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "ssl")
+	return filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+}
+
+func readFile(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestEnsureCertificate_Generate(t *testing.T) {
+	certFile, keyFile := testPaths(t)
+
+	if err := ensureCertificate(certFile, keyFile); err != nil {
+		t.Fatalf("failed to generate certificate: %v", err)
+	}
+
+	if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+		t.Errorf("expected a valid key pair, got %v", err)
+	}
+	info, err := os.Stat(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("expected key mode 0600, got %o", mode)
+	}
+}
+
+func TestEnsureCertificate_Reuse(t *testing.T) {
+	certFile, keyFile := testPaths(t)
+	if err := ensureCertificate(certFile, keyFile); err != nil {
+		t.Fatalf("failed to generate certificate: %v", err)
+	}
+	certPEM, keyPEM := readFile(t, certFile), readFile(t, keyFile)
+
+	if err := ensureCertificate(certFile, keyFile); err != nil {
+		t.Fatalf("failed to reuse certificate: %v", err)
+	}
+
+	if !bytes.Equal(readFile(t, certFile), certPEM) || !bytes.Equal(readFile(t, keyFile), keyPEM) {
+		t.Error("expected the existing certificate and key to be kept")
+	}
+}
+
+func TestEnsureCertificate_HalfPresent(t *testing.T) {
+	tests := []struct {
+		name    string
+		present func(certFile, keyFile string) (string, string)
+	}{
+		{name: "certificate only", present: func(certFile, keyFile string) (string, string) { return certFile, keyFile }},
+		{name: "key only", present: func(certFile, keyFile string) (string, string) { return keyFile, certFile }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			certFile, keyFile := testPaths(t)
+			present, missing := tt.present(certFile, keyFile)
+			if err := os.MkdirAll(filepath.Dir(present), 0755); err != nil {
+				t.Fatal(err)
+			}
+			supplied := []byte("supplied by the operator")
+			if err := os.WriteFile(present, supplied, 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := ensureCertificate(certFile, keyFile); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			if !bytes.Equal(readFile(t, present), supplied) {
+				t.Errorf("expected %s to be left alone", present)
+			}
+			if _, err := os.Stat(missing); !os.IsNotExist(err) {
+				t.Errorf("expected %s not to be generated", missing)
+			}
+		})
+	}
+}
+
+func TestServerTLSConfig_ClientCert(t *testing.T) {
+	certFile, keyFile := testPaths(t)
+	cfg := config.Grpc{CertFile: certFile, KeyFile: keyFile}
+
+	if _, err := ServerTLSConfig(cfg, true); err == nil {
+		t.Error("expected error without a client certificate")
+	}
+
+	tlsConfig, err := ServerTLSConfig(cfg, false)
+	if err != nil {
+		t.Fatalf("failed to create TLS config: %v", err)
+	}
+	if tlsConfig.ClientAuth != tls.NoClientCert {
+		t.Errorf("expected no client auth, got %v", tlsConfig.ClientAuth)
+	}
+
+	// The node certificate doubles as the panel CA here.
+	cfg.ClientCertFile = certFile
+	tlsConfig, err = ServerTLSConfig(cfg, true)
+	if err != nil {
+		t.Fatalf("failed to create TLS config: %v", err)
+	}
+	if tlsConfig.ClientAuth != tls.RequireAndVerifyClientCert || tlsConfig.ClientCAs == nil {
+		t.Errorf("expected verified client certificates, got %v", tlsConfig.ClientAuth)
+	}
+
+	cfg.ClientCertFile = keyFile
+	if _, err := ServerTLSConfig(cfg, true); err == nil {
+		t.Error("expected error for a client certificate file without certificates")
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// BackendTypeSingBox is the only backend type the node can run.
+const BackendTypeSingBox = "sing-box"
+
+// Backend is a core the node runs. AssetsPath is the directory relative
+// paths in the core config, such as rule sets, resolve against. It defaults
+// to the working directory of the node.
+type Backend struct {
+	Name           string `yaml:"name"`
+	Type           string `yaml:"type"`
+	ExecutablePath string `yaml:"executable_path"`
+	ConfigPath     string `yaml:"config_path"`
+	AssetsPath     string `yaml:"assets_path"`
+	Enabled        bool   `yaml:"enabled"`
+}
+
+// UnmarshalYAML makes backends enabled unless stated otherwise.
+func (b *Backend) UnmarshalYAML(value *yaml.Node) error {
+	type plain Backend
+	backend := plain{Enabled: true}
+	if err := value.Decode(&backend); err != nil {
+		return err
+	}
+	*b = Backend(backend)
+	return nil
+}
+
+type backendsFile struct {
+	Backends []Backend `yaml:"backends"`
+}
+
+func LoadBackends(path string) ([]Backend, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading backends file %s", path)
+	}
+
+	var file backendsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, errors.Wrapf(err, "error parsing backends file %s", path)
+	}
+
+	names := make(map[string]struct{}, len(file.Backends))
+	for i := range file.Backends {
+		backend := &file.Backends[i]
+		if backend.Name == "" {
+			backend.Name = backend.Type
+		}
+		if _, exists := names[backend.Name]; exists {
+			return nil, errors.Errorf("backend %d: duplicate name %q", i, backend.Name)
+		}
+		names[backend.Name] = struct{}{}
+
+		if backend.Type != BackendTypeSingBox {
+			return nil, errors.Errorf("backend %d: unsupported type %q, only %s is supported", i, backend.Type, BackendTypeSingBox)
+		}
+		if backend.ExecutablePath == "" {
+			return nil, errors.Errorf("backend %d: executable_path is required", i)
+		}
+		if backend.ConfigPath == "" {
+			return nil, errors.Errorf("backend %d: config_path is required", i)
+		}
+		if backend.AssetsPath != "" {
+			info, err := os.Stat(backend.AssetsPath)
+			if err != nil {
+				return nil, errors.Wrapf(err, "backend %d: error reading assets_path", i)
+			}
+			if !info.IsDir() {
+				return nil, errors.Errorf("backend %d: assets_path %s is not a directory", i, backend.AssetsPath)
+			}
+		}
+	}
+
+	return file.Backends, nil
+}
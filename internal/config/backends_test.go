@@ -0,0 +1,186 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeBackends(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "backends.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadBackends(t *testing.T) {
+	assetsDir := t.TempDir()
+	assetsFile := filepath.Join(assetsDir, "geoip.db")
+	if err := os.WriteFile(assetsFile, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []Backend
+		err     string
+	}{
+		{
+			name: "defaults",
+			content: `
+backends:
+  - type: sing-box
+    executable_path: /usr/bin/sing-box
+    config_path: config.json
+`,
+			want: []Backend{{Name: "sing-box", Type: "sing-box", ExecutablePath: "/usr/bin/sing-box", ConfigPath: "config.json", Enabled: true}},
+		},
+		{
+			name: "assets path",
+			content: `
+backends:
+  - type: sing-box
+    executable_path: /usr/bin/sing-box
+    config_path: config.json
+    assets_path: ` + assetsDir + `
+    enabled: false
+`,
+			want: []Backend{{Name: "sing-box", Type: "sing-box", ExecutablePath: "/usr/bin/sing-box", ConfigPath: "config.json", AssetsPath: assetsDir}},
+		},
+		{
+			name: "missing assets path",
+			content: `
+backends:
+  - type: sing-box
+    executable_path: /usr/bin/sing-box
+    config_path: config.json
+    assets_path: ` + filepath.Join(assetsDir, "missing") + `
+`,
+			err: "error reading assets_path",
+		},
+		{
+			name: "assets path is a file",
+			content: `
+backends:
+  - type: sing-box
+    executable_path: /usr/bin/sing-box
+    config_path: config.json
+    assets_path: ` + assetsFile + `
+`,
+			err: "is not a directory",
+		},
+		{
+			name: "several instances of one type",
+			content: `
+backends:
+  - name: sing-box-a
+    type: sing-box
+    executable_path: /usr/bin/sing-box
+    config_path: a.json
+  - name: sing-box-b
+    type: sing-box
+    executable_path: /usr/bin/sing-box
+    config_path: b.json
+`,
+			want: []Backend{
+				{Name: "sing-box-a", Type: "sing-box", ExecutablePath: "/usr/bin/sing-box", ConfigPath: "a.json", Enabled: true},
+				{Name: "sing-box-b", Type: "sing-box", ExecutablePath: "/usr/bin/sing-box", ConfigPath: "b.json", Enabled: true},
+			},
+		},
+		{
+			name: "duplicate name",
+			content: `
+backends:
+  - name: edge
+    type: sing-box
+    executable_path: /usr/bin/sing-box
+    config_path: a.json
+  - name: edge
+    type: sing-box
+    executable_path: /usr/bin/sing-box
+    config_path: b.json
+`,
+			err: `backend 1: duplicate name "edge"`,
+		},
+		{
+			name: "duplicate default name",
+			content: `
+backends:
+  - type: sing-box
+    executable_path: /usr/bin/sing-box
+    config_path: a.json
+  - type: sing-box
+    executable_path: /usr/bin/sing-box
+    config_path: b.json
+`,
+			err: `backend 1: duplicate name "sing-box"`,
+		},
+		{
+			name: "unsupported type",
+			content: `
+backends:
+  - name: xray
+    type: xray
+    executable_path: /usr/bin/xray
+    config_path: xray.json
+`,
+			err: `backend 0: unsupported type "xray"`,
+		},
+		{
+			name: "missing type",
+			content: `
+backends:
+  - name: core
+    executable_path: /usr/bin/sing-box
+    config_path: config.json
+`,
+			err: `backend 0: unsupported type ""`,
+		},
+		{
+			name: "missing executable path",
+			content: `
+backends:
+  - type: sing-box
+    config_path: config.json
+`,
+			err: "executable_path is required",
+		},
+		{
+			name: "missing config path",
+			content: `
+backends:
+  - type: sing-box
+    executable_path: /usr/bin/sing-box
+`,
+			err: "config_path is required",
+		},
+		{
+			name:    "malformed file",
+			content: "backends: [",
+			err:     "error parsing backends file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backends, err := LoadBackends(writeBackends(t, tt.content))
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(backends, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, backends)
+			}
+		})
+	}
+}
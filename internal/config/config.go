@@ -5,13 +5,58 @@ import "time"
 const EnvPath = ""
 
 type AppConfig struct {
-	LogLevel   string `envconfig:"LOG_LEVEL" required:"true"`
-	PostgresDB PostgresDB
-	Grpc       Grpc
+	LogLevel     string `envconfig:"LOG_LEVEL" required:"true"`
+	PostgresDB   PostgresDB
+	Grpc         Grpc
+	Auth         Auth
+	Gateway      Gateway
+	Usage        Usage
+	Audit        Audit
+	BackendsFile string    `envconfig:"BACKENDS_FILE" default:"backends.yaml"`
+	Backends     []Backend `ignored:"true"`
 }
 
 type Grpc struct {
-	Port string `envconfig:"PORT" required:"true"`
+	Port           string `envconfig:"PORT" required:"true"`
+	CertFile       string `envconfig:"SSL_CERT_FILE" default:"ssl_cert.pem"`
+	KeyFile        string `envconfig:"SSL_KEY_FILE" default:"ssl_key.pem"`
+	ClientCertFile string `envconfig:"SSL_CLIENT_CERT_FILE"`
+	Insecure       bool   `envconfig:"INSECURE" default:"false"`
+
+	HealthCheckInterval time.Duration `envconfig:"HEALTH_CHECK_INTERVAL" default:"2s"`
+}
+
+// Auth selects how clients authenticate. In "mtls" mode they present a
+// certificate signed by the panel CA, in "token" mode a bearer token, which
+// works behind proxies that terminate TLS. PreviousToken keeps the token
+// AUTH_TOKEN replaced working for the grace period after startup.
+type Auth struct {
+	Mode              string        `envconfig:"AUTH_MODE" default:"mtls"`
+	Token             string        `envconfig:"AUTH_TOKEN"`
+	PreviousToken     string        `envconfig:"AUTH_PREVIOUS_TOKEN"`
+	TokenFile         string        `envconfig:"AUTH_TOKEN_FILE"`
+	ReloadInterval    time.Duration `envconfig:"AUTH_TOKEN_RELOAD_INTERVAL" default:"30s"`
+	GracePeriod       time.Duration `envconfig:"AUTH_TOKEN_GRACE_PERIOD" default:"1h"`
+	SignedTokenMaxAge time.Duration `envconfig:"AUTH_SIGNED_TOKEN_MAX_AGE" default:"5m"`
+}
+
+// Gateway is the optional HTTP/JSON listener. It is disabled unless a port
+// is set, and uses the same TLS settings as the gRPC server.
+type Gateway struct {
+	Port string `envconfig:"GATEWAY_PORT"`
+}
+
+type Usage struct {
+	LedgerPath      string        `envconfig:"USAGE_LEDGER_PATH"`
+	CollectInterval time.Duration `envconfig:"USAGE_COLLECT_INTERVAL" default:"10s"`
+}
+
+// Audit is the log of state-changing calls. It is disabled unless a path is
+// set.
+type Audit struct {
+	Path       string `envconfig:"AUDIT_LOG_PATH"`
+	MaxSize    int64  `envconfig:"AUDIT_LOG_MAX_SIZE" default:"10485760"`
+	MaxBackups int    `envconfig:"AUDIT_LOG_MAX_BACKUPS" default:"5"`
 }
 
 type PostgresDB struct {
@@ -0,0 +1,512 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"marznode/api/pb"
+	"marznode/internal/api"
+	"marznode/internal/auth"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Gateway exposes MarzService as JSON over HTTP. Requests are served by the
+// same handler as the gRPC server, so both share the service layer. Bodies
+// are the protobuf messages of the matching RPC in their JSON form.
+type Gateway struct {
+	server pb.MarzServiceServer
+	app    *fiber.App
+	log    *zap.SugaredLogger
+	// auth checks bearer tokens in token auth mode, and is nil otherwise.
+	auth *auth.Authenticator
+
+	// ctx ends the log and stats streams on shutdown.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func New(server pb.MarzServiceServer, authenticator *auth.Authenticator, log *zap.SugaredLogger) *Gateway {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &Gateway{
+		server: server,
+		auth:   authenticator,
+		log:    log,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	g.app = fiber.New(fiber.Config{
+		DisableStartupMessage: true,
+		ErrorHandler:          g.handleError,
+	})
+	g.app.Use(g.logRequest)
+	g.app.Use(g.recoverPanic)
+	g.app.Use(g.authenticate)
+
+	g.app.Get("/node", g.getNodeInfo)
+
+	g.app.Post("/users/sync", g.syncUsers)
+	g.app.Post("/users/repopulate", g.repopulateUsers)
+	g.app.Get("/users/:uid", g.getUser)
+	g.app.Post("/users/:uid/disconnect", g.disconnectUser)
+
+	g.app.Get("/inbounds/counts", g.countInboundUsers)
+	g.app.Get("/inbounds/:tag/users", g.listInboundUsers)
+
+	g.app.Get("/backends", g.fetchBackends)
+	g.app.Get("/backends/:name/config", g.fetchBackendConfig)
+	g.app.Post("/backends/:name/restart", g.restartBackend)
+	g.app.Post("/backends/:name/validate", g.validateBackendConfig)
+	g.app.Post("/backends/:name/diff", g.diffBackendConfig)
+	g.app.Post("/backends/:name/inbounds", g.addInbound)
+	g.app.Put("/backends/:name/inbounds/:tag", g.replaceInbound)
+	g.app.Delete("/backends/:name/inbounds/:tag", g.removeInbound)
+	g.app.Get("/backends/:name/stats", g.getBackendStats)
+	g.app.Get("/backends/:name/logs", g.streamBackendLogs)
+
+	g.app.Get("/connections", g.listConnections)
+
+	g.app.Get("/audit", g.queryAuditLog)
+
+	g.app.Get("/stats/users", g.fetchUsersStats)
+	g.app.Post("/stats/users/collect", g.collectUsersStats)
+	g.app.Post("/stats/users/ack", g.ackUsersStats)
+	g.app.Get("/stats/users/stream", g.streamUsersStats)
+
+	return g
+}
+
+// Serve accepts connections on the listener until Shutdown is called. TLS,
+// if any, is terminated by the listener.
+func (g *Gateway) Serve(listener net.Listener) error {
+	return g.app.Listener(listener)
+}
+
+func (g *Gateway) Shutdown(ctx context.Context) error {
+	g.cancel()
+	return g.app.ShutdownWithContext(ctx)
+}
+
+func (g *Gateway) getNodeInfo(c *fiber.Ctx) error {
+	return call(c, &pb.Empty{}, g.server.GetNodeInfo)
+}
+
+func (g *Gateway) syncUsers(c *fiber.Ctx) error {
+	request := &pb.UsersData{}
+	if err := decode(c, request); err != nil {
+		return err
+	}
+
+	stream := &syncUsersStream{ctx: c.UserContext(), users: request.GetUsersData()}
+	if err := g.server.SyncUsers(stream); err != nil {
+		return err
+	}
+	return respond(c, stream.response)
+}
+
+func (g *Gateway) repopulateUsers(c *fiber.Ctx) error {
+	request := &pb.UsersData{}
+	if err := decode(c, request); err != nil {
+		return err
+	}
+	return call(c, request, g.server.RepopulateUsers)
+}
+
+func (g *Gateway) getUser(c *fiber.Ctx) error {
+	uid, err := strconv.ParseUint(c.Params("uid"), 10, 32)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid uid %q", c.Params("uid"))
+	}
+	return call(c, &pb.UserRequest{Uid: uint32(uid)}, g.server.GetUser)
+}
+
+func (g *Gateway) disconnectUser(c *fiber.Ctx) error {
+	uid, err := strconv.ParseUint(c.Params("uid"), 10, 32)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid uid %q", c.Params("uid"))
+	}
+	return call(c, &pb.UserRequest{Uid: uint32(uid)}, g.server.DisconnectUser)
+}
+
+func (g *Gateway) countInboundUsers(c *fiber.Ctx) error {
+	return call(c, &pb.Empty{}, g.server.CountInboundUsers)
+}
+
+func (g *Gateway) listInboundUsers(c *fiber.Ctx) error {
+	return call(c, &pb.InboundRequest{Tag: c.Params("tag")}, g.server.ListInboundUsers)
+}
+
+func (g *Gateway) fetchBackends(c *fiber.Ctx) error {
+	return call(c, &pb.Empty{}, g.server.FetchBackends)
+}
+
+func (g *Gateway) fetchBackendConfig(c *fiber.Ctx) error {
+	return call(c, &pb.Backend{Name: c.Params("name")}, g.server.FetchBackendConfig)
+}
+
+// restartBackend takes an optional BackendConfig as the body.
+func (g *Gateway) restartBackend(c *fiber.Ctx) error {
+	request := &pb.RestartBackendRequest{BackendName: c.Params("name")}
+	if len(c.Body()) > 0 {
+		request.Config = &pb.BackendConfig{}
+		if err := decode(c, request.Config); err != nil {
+			return err
+		}
+	}
+	return call(c, request, g.server.RestartBackend)
+}
+
+// validateBackendConfig takes an optional BackendConfig as the body.
+func (g *Gateway) validateBackendConfig(c *fiber.Ctx) error {
+	request := &pb.ValidateBackendConfigRequest{BackendName: c.Params("name")}
+	if len(c.Body()) > 0 {
+		request.Config = &pb.BackendConfig{}
+		if err := decode(c, request.Config); err != nil {
+			return err
+		}
+	}
+	return call(c, request, g.server.ValidateBackendConfig)
+}
+
+// diffBackendConfig takes the proposed BackendConfig as the body.
+func (g *Gateway) diffBackendConfig(c *fiber.Ctx) error {
+	request := &pb.DiffBackendConfigRequest{
+		BackendName: c.Params("name"),
+		Config:      &pb.BackendConfig{},
+	}
+	if err := decode(c, request.Config); err != nil {
+		return err
+	}
+	return call(c, request, g.server.DiffBackendConfig)
+}
+
+// addInbound takes the inbound config as the body.
+func (g *Gateway) addInbound(c *fiber.Ctx) error {
+	request := &pb.InboundConfigRequest{
+		BackendName: c.Params("name"),
+		Config:      string(c.Body()),
+	}
+	return call(c, request, g.server.AddInbound)
+}
+
+// replaceInbound takes the inbound config as the body. Its tag must match
+// the one in the path.
+func (g *Gateway) replaceInbound(c *fiber.Ctx) error {
+	var inbound struct {
+		Tag string `json:"tag"`
+	}
+	if err := json.Unmarshal(c.Body(), &inbound); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request body: %v", err)
+	}
+	if inbound.Tag != c.Params("tag") {
+		return status.Errorf(codes.InvalidArgument, "inbound tag %q does not match %q", inbound.Tag, c.Params("tag"))
+	}
+
+	request := &pb.InboundConfigRequest{
+		BackendName: c.Params("name"),
+		Config:      string(c.Body()),
+	}
+	return call(c, request, g.server.ReplaceInbound)
+}
+
+func (g *Gateway) removeInbound(c *fiber.Ctx) error {
+	request := &pb.RemoveInboundRequest{
+		BackendName: c.Params("name"),
+		Tag:         c.Params("tag"),
+	}
+	return call(c, request, g.server.RemoveInbound)
+}
+
+func (g *Gateway) getBackendStats(c *fiber.Ctx) error {
+	return call(c, &pb.Backend{Name: c.Params("name")}, g.server.GetBackendStats)
+}
+
+// streamBackendLogs sends log lines as server-sent events.
+func (g *Gateway) streamBackendLogs(c *fiber.Ctx) error {
+	name := c.Params("name")
+
+	// Errors cannot change the response status once the event stream has
+	// started, so report unknown and stopped backends up front.
+	stats, err := g.server.GetBackendStats(c.UserContext(), &pb.Backend{Name: name})
+	if err != nil {
+		return err
+	}
+	if !stats.GetRunning() {
+		return status.Errorf(codes.FailedPrecondition, "backend %s is not running", name)
+	}
+
+	request := &pb.BackendLogsRequest{
+		BackendName:   name,
+		IncludeBuffer: c.QueryBool("include_buffer"),
+	}
+	return streamEvents(g, c, func(stream *eventStream[pb.LogLine]) error {
+		return g.server.StreamBackendLogs(request, stream)
+	})
+}
+
+// streamUsersStats sends usage deltas as server-sent events.
+func (g *Gateway) streamUsersStats(c *fiber.Ctx) error {
+	request := &pb.StreamUsersStatsRequest{
+		IntervalSeconds: uint32(c.QueryInt("interval_seconds")),
+	}
+	if cursor := c.Query("resume_cursor"); cursor != "" {
+		request.ResumeCursor = &cursor
+	}
+	return streamEvents(g, c, func(stream *eventStream[pb.UsersStats]) error {
+		return g.server.StreamUsersStats(request, stream)
+	})
+}
+
+// listConnections takes optional backend_name and uid query parameters.
+func (g *Gateway) listConnections(c *fiber.Ctx) error {
+	request := &pb.ListConnectionsRequest{}
+	if name := c.Query("backend_name"); name != "" {
+		request.BackendName = &name
+	}
+	if c.Query("uid") != "" {
+		uid, err := strconv.ParseUint(c.Query("uid"), 10, 32)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid uid %q", c.Query("uid"))
+		}
+		uid32 := uint32(uid)
+		request.Uid = &uid32
+	}
+	return call(c, request, g.server.ListConnections)
+}
+
+// queryAuditLog takes the fields of AuditLogQuery as query parameters.
+func (g *Gateway) queryAuditLog(c *fiber.Ctx) error {
+	request := &pb.AuditLogQuery{Limit: uint32(c.QueryInt("limit"))}
+	var err error
+	if request.Since, err = queryTimestamp(c, "since"); err != nil {
+		return err
+	}
+	if request.Until, err = queryTimestamp(c, "until"); err != nil {
+		return err
+	}
+	if c.Query("uid") != "" {
+		uid, err := strconv.ParseUint(c.Query("uid"), 10, 32)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid uid %q", c.Query("uid"))
+		}
+		uid32 := uint32(uid)
+		request.Uid = &uid32
+	}
+	if rpc := c.Query("rpc"); rpc != "" {
+		request.Rpc = &rpc
+	}
+	if name := c.Query("backend_name"); name != "" {
+		request.BackendName = &name
+	}
+	return call(c, request, g.server.QueryAuditLog)
+}
+
+func queryTimestamp(c *fiber.Ctx, key string) (*uint64, error) {
+	if c.Query(key) == "" {
+		return nil, nil
+	}
+	value, err := strconv.ParseUint(c.Query(key), 10, 64)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s %q", key, c.Query(key))
+	}
+	return &value, nil
+}
+
+func (g *Gateway) fetchUsersStats(c *fiber.Ctx) error {
+	return call(c, &pb.Empty{}, g.server.FetchUsersStats)
+}
+
+func (g *Gateway) collectUsersStats(c *fiber.Ctx) error {
+	request := &pb.CollectUsersStatsRequest{}
+	if err := decode(c, request); err != nil {
+		return err
+	}
+	return call(c, request, g.server.CollectUsersStats)
+}
+
+func (g *Gateway) ackUsersStats(c *fiber.Ctx) error {
+	request := &pb.AckUsersStatsRequest{}
+	if err := decode(c, request); err != nil {
+		return err
+	}
+	return call(c, request, g.server.AckUsersStats)
+}
+
+// logRequest tags the request with an ID and logs it the same way the gRPC
+// interceptors log calls. The client is added to the context as a gRPC peer,
+// so the handler sees HTTP and gRPC callers alike.
+func (g *Gateway) logRequest(c *fiber.Ctx) error {
+	ctx := api.WithRequestID(c.UserContext(), c.Get(api.RequestIDKey))
+	ctx = peer.NewContext(ctx, requestPeer(c))
+	c.SetUserContext(ctx)
+	c.Set(api.RequestIDKey, api.RequestID(ctx))
+
+	start := time.Now()
+	err := c.Next()
+	if err != nil {
+		if handlerErr := c.App().ErrorHandler(c, err); handlerErr != nil {
+			_ = c.SendStatus(fiber.StatusInternalServerError)
+		}
+	}
+
+	fields := []any{
+		"method", c.Method(),
+		"path", c.Path(),
+		"duration", time.Since(start),
+		"status", c.Response().StatusCode(),
+		"peer", c.Context().RemoteAddr().String(),
+		"request_id", api.RequestID(ctx),
+	}
+	if err != nil {
+		g.log.Warnw("HTTP call", append(fields, "error", err)...)
+	} else {
+		g.log.Infow("HTTP call", fields...)
+	}
+	return nil
+}
+
+// recoverPanic turns a panic in a handler into an Internal error. Like the
+// gRPC recovery interceptor, it keeps the panic value out of the response.
+func (g *Gateway) recoverPanic(c *fiber.Ctx) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			g.log.Errorw("Recovered from panic in HTTP handler",
+				"method", c.Method(),
+				"path", c.Path(),
+				"request_id", api.RequestID(c.UserContext()),
+				"panic", r,
+				"stack", string(debug.Stack()),
+			)
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return c.Next()
+}
+
+// authenticate checks the bearer token of the request in token auth mode.
+func (g *Gateway) authenticate(c *fiber.Ctx) error {
+	if g.auth == nil {
+		return c.Next()
+	}
+
+	ctx, err := g.auth.AuthenticateHeader(c.UserContext(), c.Get(fiber.HeaderAuthorization))
+	if err != nil {
+		return err
+	}
+	c.SetUserContext(ctx)
+	return c.Next()
+}
+
+// handleError writes a gRPC status, including its details, as the JSON body
+// of the matching HTTP status.
+func (g *Gateway) handleError(c *fiber.Ctx, err error) error {
+	if fiberErr, ok := err.(*fiber.Error); ok {
+		err = status.Error(codeFromHTTP(fiberErr.Code), fiberErr.Message)
+	}
+
+	st := status.Convert(err)
+	data, marshalErr := protojson.Marshal(st.Proto())
+	if marshalErr != nil {
+		return marshalErr
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Status(httpFromCode(st.Code())).Send(data)
+}
+
+func requestPeer(c *fiber.Ctx) *peer.Peer {
+	p := &peer.Peer{Addr: c.Context().RemoteAddr()}
+	if state := c.Context().TLSConnectionState(); state != nil {
+		p.AuthInfo = credentials.TLSInfo{State: *state}
+	}
+	return p
+}
+
+type unaryCall[Req, Res proto.Message] func(context.Context, Req) (Res, error)
+
+func call[Req, Res proto.Message](c *fiber.Ctx, request Req, rpc unaryCall[Req, Res]) error {
+	response, err := rpc(c.UserContext(), request)
+	if err != nil {
+		return err
+	}
+	return respond(c, response)
+}
+
+func decode(c *fiber.Ctx, msg proto.Message) error {
+	if len(c.Body()) == 0 {
+		return nil
+	}
+	if err := protojson.Unmarshal(c.Body(), msg); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request body: %v", err)
+	}
+	return nil
+}
+
+func respond(c *fiber.Ctx, msg proto.Message) error {
+	data, err := protojson.Marshal(msg)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal response: %v", err)
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(data)
+}
+
+func httpFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func codeFromHTTP(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return codes.NotFound
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
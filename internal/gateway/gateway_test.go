@@ -0,0 +1,311 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"marznode/api/pb"
+	"marznode/internal/auth"
+	"marznode/internal/config"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testToken = "0123456789abcdef0123456789abcdef"
+
+type testServer struct {
+	pb.UnimplementedMarzServiceServer
+
+	err       error
+	panicking bool
+	subject   string
+
+	running       bool
+	lines         []string
+	streamErr     error
+	includeBuffer bool
+}
+
+func (s *testServer) GetNodeInfo(ctx context.Context, request *pb.Empty) (*pb.NodeInfo, error) {
+	if s.panicking {
+		panic("secret panic value")
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.subject = auth.Subject(ctx)
+	return &pb.NodeInfo{Version: "test"}, nil
+}
+
+func (s *testServer) GetBackendStats(ctx context.Context, request *pb.Backend) (*pb.BackendStats, error) {
+	if request.GetName() != "sing-box" {
+		return nil, status.Errorf(codes.NotFound, "backend %s not found", request.GetName())
+	}
+	return &pb.BackendStats{Running: s.running}, nil
+}
+
+func (s *testServer) StreamBackendLogs(request *pb.BackendLogsRequest, stream grpc.ServerStreamingServer[pb.LogLine]) error {
+	s.includeBuffer = request.GetIncludeBuffer()
+	for _, line := range s.lines {
+		if err := stream.Send(&pb.LogLine{Line: line}); err != nil {
+			return err
+		}
+	}
+	return s.streamErr
+}
+
+func newTestGateway(t *testing.T, server pb.MarzServiceServer, authenticator *auth.Authenticator) *Gateway {
+	t.Helper()
+	g := New(server, authenticator, zap.NewNop().Sugar())
+	t.Cleanup(func() { _ = g.Shutdown(context.Background()) })
+	return g
+}
+
+func newTestAuthenticator(t *testing.T) *auth.Authenticator {
+	t.Helper()
+	authenticator, err := auth.New(config.Auth{
+		Mode:              auth.ModeToken,
+		Token:             testToken,
+		GracePeriod:       time.Hour,
+		SignedTokenMaxAge: 5 * time.Minute,
+	}, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatalf("failed to create authenticator: %v", err)
+	}
+	return authenticator
+}
+
+func doRequest(t *testing.T, g *Gateway, req *http.Request) (*http.Response, string) {
+	t.Helper()
+	resp, err := g.app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("request %s %s failed: %v", req.Method, req.URL, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read response body: %v", err)
+	}
+	return resp, string(body)
+}
+
+// decodeStatus reads a gRPC status written as JSON by handleError.
+func decodeStatus(t *testing.T, body string) (codes.Code, string) {
+	t.Helper()
+	var st struct {
+		Code    codes.Code `json:"code"`
+		Message string     `json:"message"`
+	}
+	if err := json.Unmarshal([]byte(body), &st); err != nil {
+		t.Fatalf("expected a JSON status, got %q: %v", body, err)
+	}
+	return st.Code, st.Message
+}
+
+func TestGateway_Authenticate(t *testing.T) {
+	tests := []struct {
+		name       string
+		tokenMode  bool
+		header     string
+		wantStatus int
+	}{
+		{name: "token mode without header", tokenMode: true, wantStatus: http.StatusUnauthorized},
+		{name: "token mode with wrong token", tokenMode: true, header: "Bearer wrong-token-0123456789", wantStatus: http.StatusUnauthorized},
+		{name: "token mode with other scheme", tokenMode: true, header: "Basic " + testToken, wantStatus: http.StatusUnauthorized},
+		{name: "token mode with token", tokenMode: true, header: "Bearer " + testToken, wantStatus: http.StatusOK},
+		{name: "mtls mode without header", wantStatus: http.StatusOK},
+		{name: "mtls mode ignores header", header: "Bearer wrong-token-0123456789", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var authenticator *auth.Authenticator
+			if tt.tokenMode {
+				authenticator = newTestAuthenticator(t)
+			}
+			server := &testServer{}
+			g := newTestGateway(t, server, authenticator)
+
+			req := httptest.NewRequest(http.MethodGet, "/node", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			resp, body := doRequest(t, g, req)
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, resp.StatusCode, body)
+			}
+			if resp.StatusCode == http.StatusUnauthorized {
+				if code, _ := decodeStatus(t, body); code != codes.Unauthenticated {
+					t.Errorf("expected code %s, got %s", codes.Unauthenticated, code)
+				}
+				return
+			}
+			if tt.tokenMode && server.subject == "" {
+				t.Error("expected the handler to see the authenticated subject")
+			}
+			if !tt.tokenMode && server.subject != "" {
+				t.Errorf("expected no subject in mtls mode, got %q", server.subject)
+			}
+		})
+	}
+}
+
+func TestGateway_HandleError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   codes.Code
+	}{
+		{name: "invalid argument", err: status.Error(codes.InvalidArgument, "bad"), wantStatus: http.StatusBadRequest, wantCode: codes.InvalidArgument},
+		{name: "failed precondition", err: status.Error(codes.FailedPrecondition, "bad"), wantStatus: http.StatusBadRequest, wantCode: codes.FailedPrecondition},
+		{name: "not found", err: status.Error(codes.NotFound, "missing"), wantStatus: http.StatusNotFound, wantCode: codes.NotFound},
+		{name: "already exists", err: status.Error(codes.AlreadyExists, "dup"), wantStatus: http.StatusConflict, wantCode: codes.AlreadyExists},
+		{name: "permission denied", err: status.Error(codes.PermissionDenied, "no"), wantStatus: http.StatusForbidden, wantCode: codes.PermissionDenied},
+		{name: "unauthenticated", err: status.Error(codes.Unauthenticated, "who"), wantStatus: http.StatusUnauthorized, wantCode: codes.Unauthenticated},
+		{name: "resource exhausted", err: status.Error(codes.ResourceExhausted, "slow down"), wantStatus: http.StatusTooManyRequests, wantCode: codes.ResourceExhausted},
+		{name: "unimplemented", err: status.Error(codes.Unimplemented, "later"), wantStatus: http.StatusNotImplemented, wantCode: codes.Unimplemented},
+		{name: "unavailable", err: status.Error(codes.Unavailable, "down"), wantStatus: http.StatusServiceUnavailable, wantCode: codes.Unavailable},
+		{name: "deadline exceeded", err: status.Error(codes.DeadlineExceeded, "late"), wantStatus: http.StatusGatewayTimeout, wantCode: codes.DeadlineExceeded},
+		{name: "internal", err: status.Error(codes.Internal, "broken"), wantStatus: http.StatusInternalServerError, wantCode: codes.Internal},
+		{name: "plain error", err: errors.New("broken"), wantStatus: http.StatusInternalServerError, wantCode: codes.Unknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGateway(t, &testServer{err: tt.err}, nil)
+
+			resp, body := doRequest(t, g, httptest.NewRequest(http.MethodGet, "/node", nil))
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+			if got := resp.Header.Get("Content-Type"); got != "application/json" {
+				t.Errorf("expected a JSON body, got content type %q", got)
+			}
+			code, message := decodeStatus(t, body)
+			if code != tt.wantCode {
+				t.Errorf("expected code %s, got %s", tt.wantCode, code)
+			}
+			if message != status.Convert(tt.err).Message() {
+				t.Errorf("expected message %q, got %q", status.Convert(tt.err).Message(), message)
+			}
+		})
+	}
+
+	t.Run("unknown route", func(t *testing.T) {
+		g := newTestGateway(t, &testServer{}, nil)
+
+		resp, body := doRequest(t, g, httptest.NewRequest(http.MethodGet, "/missing", nil))
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, resp.StatusCode)
+		}
+		if code, _ := decodeStatus(t, body); code != codes.NotFound {
+			t.Errorf("expected code %s, got %s", codes.NotFound, code)
+		}
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		g := newTestGateway(t, &testServer{panicking: true}, nil)
+
+		resp, body := doRequest(t, g, httptest.NewRequest(http.MethodGet, "/node", nil))
+		if resp.StatusCode != http.StatusInternalServerError {
+			t.Errorf("expected status %d, got %d", http.StatusInternalServerError, resp.StatusCode)
+		}
+		if strings.Contains(body, "secret panic value") {
+			t.Errorf("expected the panic value to stay out of the response, got %s", body)
+		}
+	})
+}
+
+// readEvents splits a server-sent event stream into its events, each a map
+// of field names to values.
+func readEvents(body string) []map[string]string {
+	var events []map[string]string
+	for _, block := range strings.Split(body, "\n\n") {
+		event := make(map[string]string)
+		for _, line := range strings.Split(block, "\n") {
+			if line == "" || strings.HasPrefix(line, ":") {
+				continue
+			}
+			field, value, _ := strings.Cut(line, ": ")
+			event[field] = value
+		}
+		if len(event) > 0 {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func TestGateway_StreamBackendLogs(t *testing.T) {
+	t.Run("streams lines", func(t *testing.T) {
+		server := &testServer{
+			running:   true,
+			lines:     []string{"first", "second"},
+			streamErr: status.Error(codes.Unavailable, "core stopped"),
+		}
+		g := newTestGateway(t, server, nil)
+
+		resp, body := doRequest(t, g, httptest.NewRequest(http.MethodGet, "/backends/sing-box/logs?include_buffer=true", nil))
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, resp.StatusCode, body)
+		}
+		if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+			t.Errorf("expected an event stream, got content type %q", got)
+		}
+		if !server.includeBuffer {
+			t.Error("expected include_buffer to be passed to the RPC")
+		}
+
+		events := readEvents(body)
+		if len(events) != 3 {
+			t.Fatalf("expected 2 log events and an error event, got %v", events)
+		}
+		for i, want := range server.lines {
+			var line pb.LogLine
+			if err := json.Unmarshal([]byte(events[i]["data"]), &line); err != nil {
+				t.Fatalf("failed to decode event %d: %v", i, err)
+			}
+			if line.Line != want {
+				t.Errorf("expected line %q, got %q", want, line.Line)
+			}
+		}
+		if events[2]["event"] != "error" {
+			t.Errorf("expected the last event to be an error, got %v", events[2])
+		}
+		if code, message := decodeStatus(t, events[2]["data"]); code != codes.Unavailable || message != "core stopped" {
+			t.Errorf("expected the error of the RPC, got %s %q", code, message)
+		}
+	})
+
+	t.Run("backend not running", func(t *testing.T) {
+		g := newTestGateway(t, &testServer{}, nil)
+
+		resp, body := doRequest(t, g, httptest.NewRequest(http.MethodGet, "/backends/sing-box/logs", nil))
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+		}
+		if code, _ := decodeStatus(t, body); code != codes.FailedPrecondition {
+			t.Errorf("expected code %s, got %s", codes.FailedPrecondition, code)
+		}
+	})
+
+	t.Run("unknown backend", func(t *testing.T) {
+		g := newTestGateway(t, &testServer{}, nil)
+
+		resp, _ := doRequest(t, g, httptest.NewRequest(http.MethodGet, "/backends/xray/logs", nil))
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("expected status %d, got %d", http.StatusNotFound, resp.StatusCode)
+		}
+	})
+}
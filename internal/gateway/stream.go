@@ -0,0 +1,146 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"marznode/api/pb"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// keepAliveInterval is how often an idle event stream is written to, which
+// is how a client that went away is noticed.
+const keepAliveInterval = 15 * time.Second
+
+// streamEvents runs a server-streaming RPC and writes its messages to the
+// response as server-sent events. An error ending the RPC is sent as an
+// "error" event.
+func streamEvents[T any](g *Gateway, c *fiber.Ctx, run func(*eventStream[T]) error) error {
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+
+	parent := c.UserContext()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithCancel(parent)
+		defer cancel()
+		stop := context.AfterFunc(g.ctx, cancel)
+		defer stop()
+
+		stream := &eventStream[T]{ctx: ctx, cancel: cancel, w: w}
+		defer stream.close()
+		go stream.keepAlive()
+
+		if err := run(stream); err != nil && ctx.Err() == nil {
+			stream.sendError(err)
+		}
+	})
+	return nil
+}
+
+// eventStream adapts an HTTP response to a gRPC server stream. Only the
+// methods handlers use are implemented.
+type eventStream[T any] struct {
+	grpc.ServerStream
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	w      *bufio.Writer
+	closed bool
+}
+
+func (s *eventStream[T]) Context() context.Context {
+	return s.ctx
+}
+
+func (s *eventStream[T]) Send(msg *T) error {
+	data, err := protojson.Marshal(any(msg).(proto.Message))
+	if err != nil {
+		return err
+	}
+	return s.write("data: " + string(data) + "\n\n")
+}
+
+func (s *eventStream[T]) sendError(err error) {
+	data, marshalErr := protojson.Marshal(status.Convert(err).Proto())
+	if marshalErr != nil {
+		return
+	}
+	_ = s.write("event: error\ndata: " + string(data) + "\n\n")
+}
+
+func (s *eventStream[T]) keepAlive() {
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			_ = s.write(": keep-alive\n\n")
+		}
+	}
+}
+
+// write sends an event right away. A failed write means the client is gone
+// and cancels the stream.
+func (s *eventStream[T]) write(event string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return io.ErrClosedPipe
+	}
+	if _, err := s.w.WriteString(event); err != nil {
+		s.cancel()
+		return err
+	}
+	if err := s.w.Flush(); err != nil {
+		s.cancel()
+		return err
+	}
+	return nil
+}
+
+// close stops writes once the response is handed back to the server.
+func (s *eventStream[T]) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+}
+
+// syncUsersStream feeds a decoded list of users to SyncUsers as if they were
+// streamed by a client.
+type syncUsersStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	users    []*pb.UserData
+	response *pb.SyncUsersResponse
+}
+
+func (s *syncUsersStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *syncUsersStream) Recv() (*pb.UserData, error) {
+	if len(s.users) == 0 {
+		return nil, io.EOF
+	}
+	user := s.users[0]
+	s.users = s.users[1:]
+	return user, nil
+}
+
+func (s *syncUsersStream) SendAndClose(response *pb.SyncUsersResponse) error {
+	s.response = response
+	return nil
+}
@@ -0,0 +1,23 @@
+// Package grpcutil holds helpers shared by the gRPC interceptors.
+package grpcutil
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+// WithContext returns the stream with its context replaced, so stream
+// interceptors can pass values on to the handler.
+func WithContext(stream grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	return &contextStream{ServerStream: stream, ctx: ctx}
+}
+
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context {
+	return s.ctx
+}
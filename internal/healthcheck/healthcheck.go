@@ -0,0 +1,87 @@
+package healthcheck
+
+import (
+	"context"
+	"marznode/pkg/backend/common"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Checker drives the grpc.health.v1 service. The overall status ("") is
+// SERVING until shutdown, since the in-memory storage is always ready, and
+// every backend has a status under its name that follows whether its core is
+// running.
+//
+// A stopped core does not make the node NOT_SERVING: the panel repairs it
+// through this node, with RestartBackend and user syncs, so probes that drop
+// an unhealthy node would cut off the fix. Probes that care about a core ask
+// for it by name.
+type Checker struct {
+	server   *health.Server
+	backends []common.VPNBackend
+	statuses map[string]healthpb.HealthCheckResponse_ServingStatus
+	log      *zap.SugaredLogger
+}
+
+func NewChecker(log *zap.SugaredLogger, backends ...common.VPNBackend) *Checker {
+	c := &Checker{
+		server:   health.NewServer(),
+		backends: backends,
+		statuses: make(map[string]healthpb.HealthCheckResponse_ServingStatus),
+		log:      log,
+	}
+	c.check()
+	return c
+}
+
+func (c *Checker) Server() *health.Server {
+	return c.server
+}
+
+// Run re-evaluates the statuses every interval until ctx is done. Watch
+// subscribers are notified of every transition.
+func (c *Checker) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.check()
+		}
+	}
+}
+
+// Shutdown sets every status to NOT_SERVING and ignores later updates.
+func (c *Checker) Shutdown() {
+	c.server.Shutdown()
+}
+
+func (c *Checker) check() {
+	c.setStatus("", healthpb.HealthCheckResponse_SERVING)
+
+	for _, backend := range c.backends {
+		status := healthpb.HealthCheckResponse_NOT_SERVING
+		if backend.Running() {
+			status = healthpb.HealthCheckResponse_SERVING
+		}
+		c.setStatus(backend.Name(), status)
+	}
+}
+
+func (c *Checker) setStatus(service string, status healthpb.HealthCheckResponse_ServingStatus) {
+	if previous, ok := c.statuses[service]; ok && previous == status {
+		return
+	}
+	c.statuses[service] = status
+
+	if service != "" {
+		c.log.Infof("Backend %s is %s", service, status)
+	}
+	c.server.SetServingStatus(service, status)
+}
@@ -0,0 +1,93 @@
+package healthcheck
+
+import (
+	"context"
+	"marznode/pkg/backend/common"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type testBackend struct {
+	common.VPNBackend
+	name    string
+	running atomic.Bool
+}
+
+func (b *testBackend) Name() string {
+	return b.name
+}
+
+func (b *testBackend) Running() bool {
+	return b.running.Load()
+}
+
+func checkStatus(t *testing.T, c *Checker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+	response, err := c.Server().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		t.Fatalf("failed to check %q: %v", service, err)
+	}
+	return response.Status
+}
+
+func waitForStatus(t *testing.T, c *Checker, service string, want healthpb.HealthCheckResponse_ServingStatus) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for checkStatus(t, c, service) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %q to become %s, got %s", service, want, checkStatus(t, c, service))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestChecker_Transitions(t *testing.T) {
+	first, second := &testBackend{name: "first"}, &testBackend{name: "second"}
+	first.running.Store(true)
+	c := NewChecker(zap.NewNop().Sugar(), first, second)
+
+	tests := []struct {
+		service string
+		want    healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{service: "", want: healthpb.HealthCheckResponse_SERVING},
+		{service: "first", want: healthpb.HealthCheckResponse_SERVING},
+		{service: "second", want: healthpb.HealthCheckResponse_NOT_SERVING},
+	}
+	for _, tt := range tests {
+		if got := checkStatus(t, c, tt.service); got != tt.want {
+			t.Errorf("expected initial status of %q to be %s, got %s", tt.service, tt.want, got)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Run(ctx, 10*time.Millisecond)
+	}()
+
+	first.running.Store(false)
+	second.running.Store(true)
+	waitForStatus(t, c, "first", healthpb.HealthCheckResponse_NOT_SERVING)
+	waitForStatus(t, c, "second", healthpb.HealthCheckResponse_SERVING)
+
+	second.running.Store(false)
+	waitForStatus(t, c, "second", healthpb.HealthCheckResponse_NOT_SERVING)
+	if got := checkStatus(t, c, ""); got != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("expected the node to stay SERVING with every core stopped, got %s", got)
+	}
+
+	cancel()
+	<-done
+	c.Shutdown()
+	for _, service := range []string{"", "first", "second"} {
+		if got := checkStatus(t, c, service); got != healthpb.HealthCheckResponse_NOT_SERVING {
+			t.Errorf("expected %q to be NOT_SERVING after shutdown, got %s", service, got)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+
+	ErrUserNotFound         = fmt.Errorf("user %w", ErrNotFound)
+	ErrInboundNotFound      = fmt.Errorf("inbound %w", ErrNotFound)
+	ErrInboundAlreadyExists = fmt.Errorf("inbound %w", ErrAlreadyExists)
+)
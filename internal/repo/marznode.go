@@ -51,7 +51,7 @@ func (r *marznodeRepository) GetUser(ctx context.Context, userID int64) (*models
 	if user, exists := r.storage.users[userID]; exists {
 		return &user, nil
 	}
-	return nil, nil // В Python возвращает None если не найден
+	return nil, fmt.Errorf("%w: %d", ErrUserNotFound, userID)
 }
 
 // ListInbounds - точный аналог Python list_inbounds
@@ -86,7 +86,7 @@ func (r *marznodeRepository) GetInbound(ctx context.Context, tag string) (*model
 	if inbound, exists := r.storage.inbounds[tag]; exists {
 		return &inbound, nil
 	}
-	return nil, fmt.Errorf("inbound with tag %s not found", tag)
+	return nil, fmt.Errorf("%w: %s", ErrInboundNotFound, tag)
 }
 
 // ListInboundUsers - точный аналог Python list_inbound_users
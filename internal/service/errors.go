@@ -0,0 +1,20 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"marznode/internal/repo"
+)
+
+// Storage errors are re-exported so callers of the service layer do not
+// depend on the repository package.
+var (
+	ErrNotFound             = repo.ErrNotFound
+	ErrAlreadyExists        = repo.ErrAlreadyExists
+	ErrUserNotFound         = repo.ErrUserNotFound
+	ErrInboundNotFound      = repo.ErrInboundNotFound
+	ErrInboundAlreadyExists = repo.ErrInboundAlreadyExists
+
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrBackendNotFound = fmt.Errorf("backend %w", ErrNotFound)
+)
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"marznode/pkg/backend/common/models"
+)
+
+// BackendStorage exposes MarznodeMemory through the context-free storage
+// interface the backends are built against.
+type BackendStorage struct {
+	memory MarznodeMemory
+}
+
+func NewBackendStorage(memory MarznodeMemory) *BackendStorage {
+	return &BackendStorage{
+		memory: memory,
+	}
+}
+
+func (s *BackendStorage) ListUsers(userID *int64) ([]models.User, error) {
+	ctx := context.Background()
+	if userID == nil {
+		return s.memory.ListUsers(ctx)
+	}
+
+	user, err := s.memory.GetUser(ctx, *userID)
+	if errors.Is(err, ErrUserNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []models.User{*user}, nil
+}
+
+func (s *BackendStorage) ListInbounds(tags []string, includeUsers bool) ([]models.Inbound, error) {
+	return s.memory.ListInbounds(context.Background(), tags, includeUsers)
+}
+
+func (s *BackendStorage) ListInboundUsers(tag string) ([]models.User, error) {
+	return s.memory.ListInboundUsers(context.Background(), tag)
+}
+
+func (s *BackendStorage) RemoveUser(user models.User) error {
+	return s.memory.RemoveUser(context.Background(), user)
+}
+
+func (s *BackendStorage) UpdateUserInbounds(user models.User, inbounds []models.Inbound) error {
+	return s.memory.UpdateUserInbounds(context.Background(), user, inbounds)
+}
+
+func (s *BackendStorage) RegisterInbound(inbound models.Inbound) error {
+	return s.memory.RegisterInbound(context.Background(), inbound)
+}
+
+func (s *BackendStorage) RemoveInbound(inbound models.Inbound) error {
+	return s.memory.RemoveInbound(context.Background(), inbound)
+}
+
+func (s *BackendStorage) FlushUsers() error {
+	return s.memory.FlushUsers(context.Background())
+}
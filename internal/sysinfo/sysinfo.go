@@ -0,0 +1,174 @@
+package sysinfo
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var procPath = "/proc"
+
+type Info struct {
+	Hostname        string
+	Kernel          string
+	CPUCount        int
+	Load1           float64
+	Load5           float64
+	Load15          float64
+	MemoryTotal     uint64
+	MemoryAvailable uint64
+	Uptime          time.Duration
+	Interfaces      []Interface
+}
+
+type Interface struct {
+	Name         string
+	HardwareAddr string
+	Addresses    []string
+	Up           bool
+}
+
+// Read collects host facts from /proc and the network stack.
+func Read() (*Info, error) {
+	info := &Info{
+		CPUCount: runtime.NumCPU(),
+	}
+
+	var err error
+	if info.Hostname, err = os.Hostname(); err != nil {
+		return nil, fmt.Errorf("failed to get hostname: %w", err)
+	}
+	if info.Kernel, err = readKernel(); err != nil {
+		return nil, err
+	}
+	if info.Load1, info.Load5, info.Load15, err = readLoadAvg(); err != nil {
+		return nil, err
+	}
+	if info.MemoryTotal, info.MemoryAvailable, err = readMemInfo(); err != nil {
+		return nil, err
+	}
+	if info.Uptime, err = readUptime(); err != nil {
+		return nil, err
+	}
+	if info.Interfaces, err = readInterfaces(); err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+func readKernel() (string, error) {
+	data, err := os.ReadFile(filepath.Join(procPath, "sys", "kernel", "osrelease"))
+	if err != nil {
+		return "", fmt.Errorf("failed to read kernel release: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func readLoadAvg() (float64, float64, float64, error) {
+	data, err := os.ReadFile(filepath.Join(procPath, "loadavg"))
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to read loadavg: %w", err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) < 3 {
+		return 0, 0, 0, fmt.Errorf("unexpected loadavg format: %q", string(data))
+	}
+
+	var loads [3]float64
+	for i := range loads {
+		if loads[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return 0, 0, 0, fmt.Errorf("failed to parse loadavg: %w", err)
+		}
+	}
+	return loads[0], loads[1], loads[2], nil
+}
+
+// readMemInfo returns the total and available memory in bytes.
+func readMemInfo() (uint64, uint64, error) {
+	file, err := os.Open(filepath.Join(procPath, "meminfo"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to read meminfo: %w", err)
+	}
+	defer file.Close()
+
+	values := make(map[string]uint64, 2)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// Lines look like "MemTotal:       16318412 kB".
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		key := strings.TrimSuffix(fields[0], ":")
+		if key != "MemTotal" && key != "MemAvailable" {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to parse %s: %w", key, err)
+		}
+		if len(fields) > 2 && fields[2] == "kB" {
+			value *= 1024
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, fmt.Errorf("failed to read meminfo: %w", err)
+	}
+
+	return values["MemTotal"], values["MemAvailable"], nil
+}
+
+func readUptime() (time.Duration, error) {
+	data, err := os.ReadFile(filepath.Join(procPath, "uptime"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read uptime: %w", err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) < 1 {
+		return 0, fmt.Errorf("unexpected uptime format: %q", string(data))
+	}
+
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse uptime: %w", err)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+func readInterfaces() ([]Interface, error) {
+	netInterfaces, err := net.Interfaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list network interfaces: %w", err)
+	}
+
+	interfaces := make([]Interface, 0, len(netInterfaces))
+	for _, netInterface := range netInterfaces {
+		addrs, err := netInterface.Addrs()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list addresses of %s: %w", netInterface.Name, err)
+		}
+
+		addresses := make([]string, 0, len(addrs))
+		for _, addr := range addrs {
+			addresses = append(addresses, addr.String())
+		}
+
+		interfaces = append(interfaces, Interface{
+			Name:         netInterface.Name,
+			HardwareAddr: netInterface.HardwareAddr.String(),
+			Addresses:    addresses,
+			Up:           netInterface.Flags&net.FlagUp != 0,
+		})
+	}
+	return interfaces, nil
+}
@@ -0,0 +1,176 @@
+package sysinfo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeProc points procPath at a directory holding the given files.
+func writeProc(t *testing.T, files map[string]string) {
+	t.Helper()
+	orig := procPath
+	t.Cleanup(func() { procPath = orig })
+	procPath = t.TempDir()
+
+	for name, content := range files {
+		path := filepath.Join(procPath, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadLoadAvg(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    [3]float64
+		wantErr bool
+	}{
+		{name: "valid", content: "0.52 1.05 2.00 3/512 12345\n", want: [3]float64{0.52, 1.05, 2}},
+		{name: "too few fields", content: "0.52 1.05\n", wantErr: true},
+		{name: "not a number", content: "0.52 high 2.00 3/512 12345\n", wantErr: true},
+		{name: "empty", content: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeProc(t, map[string]string{"loadavg": tt.content})
+
+			load1, load5, load15, err := readLoadAvg()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if got := [3]float64{load1, load5, load15}; got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReadMemInfo(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		wantTotal     uint64
+		wantAvailable uint64
+		wantErr       bool
+	}{
+		{
+			name:          "valid",
+			content:       "MemTotal:       16318412 kB\nMemFree:         1024000 kB\nMemAvailable:    8159206 kB\n",
+			wantTotal:     16318412 * 1024,
+			wantAvailable: 8159206 * 1024,
+		},
+		{
+			name:      "no available memory",
+			content:   "MemTotal:       16318412 kB\nMemFree:         1024000 kB\n",
+			wantTotal: 16318412 * 1024,
+		},
+		{
+			name:          "without unit",
+			content:       "MemTotal: 2048\nMemAvailable: 1024\n",
+			wantTotal:     2048,
+			wantAvailable: 1024,
+		},
+		{
+			name:          "short lines skipped",
+			content:       "HugePages\nMemTotal: 2048 kB\nMemAvailable: 1024 kB\n",
+			wantTotal:     2048 * 1024,
+			wantAvailable: 1024 * 1024,
+		},
+		{name: "not a number", content: "MemTotal: lots kB\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeProc(t, map[string]string{"meminfo": tt.content})
+
+			total, available, err := readMemInfo()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if total != tt.wantTotal || available != tt.wantAvailable {
+				t.Errorf("expected %d/%d, got %d/%d", tt.wantTotal, tt.wantAvailable, total, available)
+			}
+		})
+	}
+}
+
+func TestReadUptime(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "valid", content: "3600.50 7000.25\n", want: time.Hour + 500*time.Millisecond},
+		{name: "empty", content: "\n", wantErr: true},
+		{name: "not a number", content: "forever 7000.25\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeProc(t, map[string]string{"uptime": tt.content})
+
+			uptime, err := readUptime()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if uptime != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, uptime)
+			}
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	writeProc(t, map[string]string{
+		"sys/kernel/osrelease": "6.8.0-generic\n",
+		"loadavg":              "0.10 0.20 0.30 1/100 42\n",
+		"meminfo":              "MemTotal: 2048 kB\nMemAvailable: 1024 kB\n",
+		"uptime":               "60.00 120.00\n",
+	})
+
+	info, err := Read()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if info.Kernel != "6.8.0-generic" {
+		t.Errorf("expected kernel 6.8.0-generic, got %q", info.Kernel)
+	}
+	if info.Load15 != 0.3 || info.MemoryAvailable != 1024*1024 || info.Uptime != time.Minute {
+		t.Errorf("expected facts from the fixture, got %+v", info)
+	}
+}
+
+func TestRead_MissingFiles(t *testing.T) {
+	writeProc(t, map[string]string{"sys/kernel/osrelease": "6.8.0-generic\n"})
+
+	if _, err := Read(); err == nil {
+		t.Error("expected error for missing proc files")
+	}
+}
@@ -0,0 +1,334 @@
+package usage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"marznode/pkg/backend/common"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidCursor is returned when acknowledging a cursor that is not of the
+// form the ledger issues.
+var ErrInvalidCursor = errors.New("malformed usage cursor")
+
+type Entry struct {
+	Backend    string `json:"backend"`
+	UID        int64  `json:"uid"`
+	InboundTag string `json:"inbound_tag"`
+	Uplink     int64  `json:"uplink"`
+	Downlink   int64  `json:"downlink"`
+}
+
+type entryKey struct {
+	backend    string
+	uid        int64
+	inboundTag string
+}
+
+type batch struct {
+	Seq     uint64  `json:"seq"`
+	Entries []Entry `json:"entries"`
+}
+
+type ledgerState struct {
+	Epoch   string  `json:"epoch"`
+	Seq     uint64  `json:"seq"`
+	Open    []Entry `json:"open"`
+	Batches []batch `json:"batches"`
+}
+
+// Ledger accumulates traffic pulled from the backends until the panel
+// acknowledges it. Every collection seals the new traffic into a batch and
+// returns all unacknowledged batches under a cursor; acknowledging a cursor
+// drops the batches it covers. The epoch in the cursor makes acknowledgements
+// from before a ledger reset harmless.
+type Ledger struct {
+	mu    sync.Mutex
+	state ledgerState
+	path  string
+	log   *zap.SugaredLogger
+}
+
+// NewLedger creates a ledger, persisted to path unless it is empty.
+func NewLedger(path string, log *zap.SugaredLogger) (*Ledger, error) {
+	l := &Ledger{
+		path: path,
+		log:  log,
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, &l.state); err != nil {
+				return nil, errors.Wrapf(err, "error parsing usage ledger %s", path)
+			}
+		case !os.IsNotExist(err):
+			return nil, errors.Wrapf(err, "error reading usage ledger %s", path)
+		}
+	}
+
+	if l.state.Epoch == "" {
+		epoch, err := newEpoch()
+		if err != nil {
+			return nil, err
+		}
+		l.state.Epoch = epoch
+	}
+
+	return l, nil
+}
+
+// Record adds traffic to the open, not yet collected, part of the ledger.
+func (l *Ledger) Record(entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.state.Open = mergeEntries(l.state.Open, entries)
+	return l.save()
+}
+
+// Drain pulls the traffic counted by every backend into the ledger. A backend
+// that fails doesn't stop the others, and the traffic already pulled from
+// them is recorded either way since the backends have reset their counters.
+func (l *Ledger) Drain(ctx context.Context, backends []common.VPNBackend) error {
+	var entries []Entry
+	var errs []error
+	for _, backend := range backends {
+		traffic, err := backend.GetUserTraffic(ctx)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "error getting traffic of backend %s", backend.Name()))
+			continue
+		}
+		for _, t := range traffic {
+			entries = append(entries, Entry{
+				Backend:    backend.Name(),
+				UID:        t.UID,
+				InboundTag: t.InboundTag,
+				Uplink:     t.Uplink,
+				Downlink:   t.Downlink,
+			})
+		}
+	}
+
+	if err := l.Record(entries); err != nil {
+		errs = append(errs, err)
+	}
+	return combineErrors(errs)
+}
+
+// Run drains the backends every interval until ctx is done, which bounds the
+// traffic lost when a core crashes.
+func (l *Ledger) Run(ctx context.Context, backends []common.VPNBackend, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.Drain(ctx, backends); err != nil {
+				l.log.Errorf("Failed to drain usage: %v", err)
+			}
+		}
+	}
+}
+
+// Collect seals the open traffic into a new batch and returns every
+// unacknowledged batch merged, along with the cursor that acknowledges them.
+func (l *Ledger) Collect() (string, []Entry, error) {
+	if err := l.Seal(); err != nil {
+		return "", nil, err
+	}
+	return l.Since("")
+}
+
+// Seal turns the open traffic into a new batch.
+func (l *Ledger) Seal() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.state.Open) == 0 {
+		return nil
+	}
+
+	l.state.Seq++
+	l.state.Batches = append(l.state.Batches, batch{
+		Seq:     l.state.Seq,
+		Entries: l.state.Open,
+	})
+	l.state.Open = nil
+
+	return l.save()
+}
+
+// Since returns the unacknowledged batches after cursor merged, along with
+// the cursor of the last of them. An empty or unknown cursor selects every
+// unacknowledged batch.
+func (l *Ledger) Since(cursor string) (string, []Entry, error) {
+	var epoch string
+	var seq uint64
+	if cursor != "" {
+		var err error
+		if epoch, seq, err = parseCursor(cursor); err != nil {
+			return "", nil, err
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var after uint64
+	if epoch == l.state.Epoch && seq <= l.state.Seq {
+		after = seq
+	}
+
+	var entries []Entry
+	for _, b := range l.state.Batches {
+		if b.Seq > after {
+			entries = mergeEntries(entries, b.Entries)
+		}
+	}
+
+	return l.cursor(l.state.Seq), entries, nil
+}
+
+// Ack drops the batches covered by cursor. Cursors of another epoch or ahead
+// of the ledger are ignored.
+func (l *Ledger) Ack(cursor string) error {
+	if cursor == "" {
+		return nil
+	}
+
+	epoch, seq, err := parseCursor(cursor)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if epoch != l.state.Epoch || seq > l.state.Seq {
+		l.log.Warnf("Ignoring acknowledgement of unknown usage cursor %s", cursor)
+		return nil
+	}
+
+	remaining := l.state.Batches[:0]
+	for _, b := range l.state.Batches {
+		if b.Seq > seq {
+			remaining = append(remaining, b)
+		}
+	}
+	if len(remaining) == len(l.state.Batches) {
+		return nil
+	}
+	l.state.Batches = remaining
+
+	return l.save()
+}
+
+func (l *Ledger) cursor(seq uint64) string {
+	return l.state.Epoch + "-" + strconv.FormatUint(seq, 10)
+}
+
+func (l *Ledger) save() error {
+	if l.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(l.state)
+	if err != nil {
+		return errors.Wrap(err, "error encoding usage ledger")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(l.path), filepath.Base(l.path)+".*")
+	if err != nil {
+		return errors.Wrap(err, "error creating usage ledger file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "error writing usage ledger")
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "error syncing usage ledger")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "error closing usage ledger")
+	}
+
+	return errors.Wrap(os.Rename(tmp.Name(), l.path), "error replacing usage ledger")
+}
+
+func parseCursor(cursor string) (string, uint64, error) {
+	i := strings.LastIndex(cursor, "-")
+	if i < 0 {
+		return "", 0, errors.Wrapf(ErrInvalidCursor, "cursor %q", cursor)
+	}
+	seq, err := strconv.ParseUint(cursor[i+1:], 10, 64)
+	if err != nil {
+		return "", 0, errors.Wrapf(ErrInvalidCursor, "cursor %q", cursor)
+	}
+	return cursor[:i], seq, nil
+}
+
+// combineErrors returns nil for no errors and otherwise the first error,
+// annotated with the messages of the others, so its cause stays checkable.
+func combineErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	others := make([]string, 0, len(errs)-1)
+	for _, err := range errs[1:] {
+		others = append(others, err.Error())
+	}
+	if len(others) == 0 {
+		return errs[0]
+	}
+	return errors.Wrap(errs[0], strings.Join(others, "; "))
+}
+
+func newEpoch() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "error generating usage ledger epoch")
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func mergeEntries(dst, src []Entry) []Entry {
+	index := make(map[entryKey]int, len(dst))
+	for i, e := range dst {
+		index[entryKey{e.Backend, e.UID, e.InboundTag}] = i
+	}
+
+	for _, e := range src {
+		key := entryKey{e.Backend, e.UID, e.InboundTag}
+		if i, ok := index[key]; ok {
+			dst[i].Uplink += e.Uplink
+			dst[i].Downlink += e.Downlink
+			continue
+		}
+		index[key] = len(dst)
+		dst = append(dst, e)
+	}
+
+	return dst
+}
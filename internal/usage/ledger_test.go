@@ -0,0 +1,235 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"marznode/pkg/backend/common"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+type testBackend struct {
+	common.VPNBackend
+	name    string
+	traffic []common.UserTraffic
+	err     error
+}
+
+func (b *testBackend) Name() string {
+	return b.name
+}
+
+func (b *testBackend) GetUserTraffic(ctx context.Context) ([]common.UserTraffic, error) {
+	return b.traffic, b.err
+}
+
+func newTestLedger(t *testing.T, path string) *Ledger {
+	t.Helper()
+	l, err := NewLedger(path, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatalf("failed to create ledger: %v", err)
+	}
+	return l
+}
+
+func collectBatch(t *testing.T, l *Ledger, entries ...Entry) string {
+	t.Helper()
+	if err := l.Record(entries); err != nil {
+		t.Fatalf("failed to record entries: %v", err)
+	}
+	if err := l.Seal(); err != nil {
+		t.Fatalf("failed to seal ledger: %v", err)
+	}
+	cursor, _, err := l.Since("")
+	if err != nil {
+		t.Fatalf("failed to read ledger: %v", err)
+	}
+	return cursor
+}
+
+func since(t *testing.T, l *Ledger, cursor string) []Entry {
+	t.Helper()
+	_, entries, err := l.Since(cursor)
+	if err != nil {
+		t.Fatalf("failed to read ledger since %q: %v", cursor, err)
+	}
+	return entries
+}
+
+func TestLedger_SealSinceAck(t *testing.T) {
+	l := newTestLedger(t, "")
+
+	first := collectBatch(t, l,
+		Entry{Backend: "sing-box", UID: 1, InboundTag: "vless-in", Uplink: 10, Downlink: 20},
+		Entry{Backend: "sing-box", UID: 1, InboundTag: "vless-in", Uplink: 1, Downlink: 2},
+	)
+	second := collectBatch(t, l,
+		Entry{Backend: "sing-box", UID: 1, InboundTag: "vless-in", Uplink: 5, Downlink: 5},
+		Entry{Backend: "sing-box", UID: 2, InboundTag: "vless-in", Uplink: 3, Downlink: 4},
+	)
+	if first == second {
+		t.Fatalf("expected a new cursor for the second batch, got %s twice", first)
+	}
+
+	want := []Entry{
+		{Backend: "sing-box", UID: 1, InboundTag: "vless-in", Uplink: 16, Downlink: 27},
+		{Backend: "sing-box", UID: 2, InboundTag: "vless-in", Uplink: 3, Downlink: 4},
+	}
+	if got := since(t, l, ""); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected all batches merged %v, got %v", want, got)
+	}
+
+	want = []Entry{
+		{Backend: "sing-box", UID: 1, InboundTag: "vless-in", Uplink: 5, Downlink: 5},
+		{Backend: "sing-box", UID: 2, InboundTag: "vless-in", Uplink: 3, Downlink: 4},
+	}
+	if got := since(t, l, first); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected the second batch %v, got %v", want, got)
+	}
+
+	if err := l.Seal(); err != nil {
+		t.Fatalf("failed to seal ledger: %v", err)
+	}
+	if cursor, _, _ := l.Since(""); cursor != second {
+		t.Errorf("expected sealing nothing to keep cursor %s, got %s", second, cursor)
+	}
+
+	if err := l.Ack(first); err != nil {
+		t.Fatalf("failed to ack: %v", err)
+	}
+	if got := since(t, l, ""); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected only the second batch after ack %v, got %v", want, got)
+	}
+
+	if err := l.Ack(second); err != nil {
+		t.Fatalf("failed to ack: %v", err)
+	}
+	if got := since(t, l, ""); len(got) != 0 {
+		t.Errorf("expected no entries after acking everything, got %v", got)
+	}
+}
+
+func TestLedger_UnknownCursor(t *testing.T) {
+	l := newTestLedger(t, "")
+	cursor := collectBatch(t, l, Entry{Backend: "sing-box", UID: 1, Uplink: 1, Downlink: 1})
+	epoch := cursor[:strings.LastIndex(cursor, "-")]
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "foreign epoch", cursor: "0123456789abcdef-1"},
+		{name: "ahead of ledger", cursor: epoch + "-5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := since(t, l, tt.cursor); len(got) != 1 {
+				t.Errorf("expected every batch for cursor %s, got %v", tt.cursor, got)
+			}
+			if err := l.Ack(tt.cursor); err != nil {
+				t.Fatalf("expected ack of cursor %s to be ignored, got %v", tt.cursor, err)
+			}
+			if got := since(t, l, ""); len(got) != 1 {
+				t.Errorf("expected ack of cursor %s to keep the batch, got %v", tt.cursor, got)
+			}
+		})
+	}
+
+	for _, cursor := range []string{"no-seq", "noseparator"} {
+		if _, _, err := l.Since(cursor); err == nil {
+			t.Errorf("expected error for malformed cursor %q", cursor)
+		}
+		if err := l.Ack(cursor); err == nil {
+			t.Errorf("expected ack error for malformed cursor %q", cursor)
+		}
+	}
+}
+
+func TestLedger_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "usage.json")
+
+	l := newTestLedger(t, path)
+	first := collectBatch(t, l, Entry{Backend: "sing-box", UID: 1, InboundTag: "vless-in", Uplink: 1, Downlink: 2})
+	second := collectBatch(t, l, Entry{Backend: "sing-box", UID: 2, InboundTag: "vless-in", Uplink: 3, Downlink: 4})
+	if err := l.Ack(first); err != nil {
+		t.Fatalf("failed to ack: %v", err)
+	}
+	if err := l.Record([]Entry{{Backend: "sing-box", UID: 3, InboundTag: "vless-in", Uplink: 5, Downlink: 6}}); err != nil {
+		t.Fatalf("failed to record: %v", err)
+	}
+
+	reopened := newTestLedger(t, path)
+	cursor, entries, err := reopened.Since("")
+	if err != nil {
+		t.Fatalf("failed to read reopened ledger: %v", err)
+	}
+	if cursor != second {
+		t.Errorf("expected cursor %s to survive a restart, got %s", second, cursor)
+	}
+	want := []Entry{{Backend: "sing-box", UID: 2, InboundTag: "vless-in", Uplink: 3, Downlink: 4}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("expected unacknowledged batch %v, got %v", want, entries)
+	}
+
+	if err := reopened.Seal(); err != nil {
+		t.Fatalf("failed to seal reopened ledger: %v", err)
+	}
+	want = []Entry{{Backend: "sing-box", UID: 3, InboundTag: "vless-in", Uplink: 5, Downlink: 6}}
+	if got := since(t, reopened, second); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected open traffic %v to survive a restart, got %v", want, got)
+	}
+}
+
+func TestLedger_Drain(t *testing.T) {
+	l := newTestLedger(t, "")
+	backends := []common.VPNBackend{
+		&testBackend{name: "broken", err: errors.New("connection refused")},
+		&testBackend{name: "sing-box", traffic: []common.UserTraffic{
+			{UID: 1, InboundTag: "vless-in", Uplink: 10, Downlink: 20},
+		}},
+	}
+
+	err := l.Drain(context.Background(), backends)
+	if err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("expected error of the broken backend, got %v", err)
+	}
+
+	_, entries, err := l.Collect()
+	if err != nil {
+		t.Fatalf("failed to collect: %v", err)
+	}
+	want := []Entry{{Backend: "sing-box", UID: 1, InboundTag: "vless-in", Uplink: 10, Downlink: 20}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("expected traffic of the working backend %v, got %v", want, entries)
+	}
+}
+
+func TestLedger_AckMalformedCursor(t *testing.T) {
+	l := newTestLedger(t, "")
+
+	for _, cursor := range []string{"no-sequence", "nodash"} {
+		if err := l.Ack(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("expected ErrInvalidCursor for %q, got %v", cursor, err)
+		}
+	}
+}
+
+func TestCombineErrors(t *testing.T) {
+	if err := combineErrors(nil); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+
+	first, second := errors.New("first"), errors.New("second")
+	err := combineErrors([]error{first, second})
+	if !errors.Is(err, first) {
+		t.Errorf("expected the first error to be the cause, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "second") {
+		t.Errorf("expected the other errors in the message, got %v", err)
+	}
+}
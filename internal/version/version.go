@@ -0,0 +1,29 @@
+package version
+
+import "runtime/debug"
+
+// Version is the node build version, set at build time with
+// -ldflags "-X marznode/internal/version.Version=...".
+var Version = ""
+
+// Get returns the build version, falling back to the module version and VCS
+// revision recorded by the Go toolchain.
+func Get() string {
+	if Version != "" {
+		return Version
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	if info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "unknown"
+}
@@ -2,11 +2,13 @@ package common
 
 import (
 	"context"
+	"time"
 
 	"github.com/highlight-apps/node-backend/backend/common/models"
 )
 
 type VPNBackend interface {
+	Name() string
 	BackendType() string
 	ConfigFormat() int
 	Version() (string, error)
@@ -14,10 +16,89 @@ type VPNBackend interface {
 	ContainsTag(tag string) bool
 	Start(ctx context.Context, backendConfig any) error
 	Restart(ctx context.Context, backendConfig any) error
+	Stop(ctx context.Context) error
 	AddUser(ctx context.Context, user models.User, inbound models.Inbound) error
 	RemoveUser(ctx context.Context, user models.User, inbound models.Inbound) error
 	GetLogs(ctx context.Context, includeBuffer bool) (<-chan string, error)
 	GetUsages(ctx context.Context) (any, error)
+	GetUserTraffic(ctx context.Context) ([]UserTraffic, error)
 	ListInbounds(ctx context.Context) ([]models.Inbound, error)
+	AddInbound(ctx context.Context, inboundConfig any) (models.Inbound, error)
+	ReplaceInbound(ctx context.Context, inboundConfig any) (models.Inbound, error)
+	RemoveInbound(ctx context.Context, tag string) error
 	GetConfig(ctx context.Context) (any, error)
+	ValidateConfig(ctx context.Context, backendConfig any) error
+	DiffConfig(ctx context.Context, backendConfig any) (*ConfigDiff, error)
+	GetStats(ctx context.Context) (*BackendStats, error)
+	ListConnections(ctx context.Context) ([]Connection, error)
+	DisconnectUser(ctx context.Context, uid int64) (int, error)
+}
+
+// Connection is a connection the core is proxying. User is the account
+// identifier ("id.username") and is empty when the core doesn't report it.
+type Connection struct {
+	ID          string
+	User        string
+	InboundTag  string
+	Network     string
+	Source      string
+	Destination string
+	Upload      int64
+	Download    int64
+	Start       time.Time
+}
+
+// UserTraffic is the traffic of a user since the last collection. InboundTag
+// is empty when the core can't attribute the traffic to a single inbound.
+type UserTraffic struct {
+	UID        int64
+	InboundTag string
+	Uplink     int64
+	Downlink   int64
+}
+
+type BackendStats struct {
+	Running        bool
+	PID            int
+	Uptime         time.Duration
+	Restarts       int64
+	LastExitReason string
+	RSS            uint64
+	Runtime        *RuntimeStats
+}
+
+// RuntimeStats are the Go runtime stats reported by the core itself.
+type RuntimeStats struct {
+	NumGoroutine uint32
+	NumGC        uint32
+	Alloc        uint64
+	TotalAlloc   uint64
+	Sys          uint64
+	Mallocs      uint64
+	Frees        uint64
+	LiveObjects  uint64
+	PauseTotalNs uint64
+	Uptime       uint32
+}
+
+// ConfigDiff is the difference between the config of a backend on disk and a
+// proposed one. Inbounds are matched by tag.
+type ConfigDiff struct {
+	Added           []InboundDiff
+	Removed         []InboundDiff
+	Modified        []InboundDiff
+	ChangedSections []string
+	RestartRequired bool
+	ReloadRequired  bool
+}
+
+// InboundDiff describes one inbound. Ports are zero when not set.
+type InboundDiff struct {
+	Tag           string
+	Protocol      string
+	OldPort       int
+	NewPort       int
+	PortChanged   bool
+	TLSChanged    bool
+	ChangedFields []string
 }
@@ -1,9 +1,7 @@
 package common
 
 const (
-	DefaultAssetsPath            = "assets"
-	DefaultSingboxConfigPath     = "singbox_config.json"
-	DefaultSingboxExecutablePath = "testdata/sing-box"
-	DefaultXrayConfigPath        = "xray_config.json"
-	DefaultXrayExecutablePath    = "testdata/xray"
+	ConfigFormatPlain = iota
+	ConfigFormatJSON
+	ConfigFormatYAML
 )
@@ -26,6 +26,14 @@ type ProcessController interface {
 	GetBuffer() []string
 	SubscribeLogs(ctx context.Context) <-chan string
 	SetOnStop(fn func())
+	ProcessInfo() ProcessInfo
+}
+
+// ProcessInfo describes the current or last process run by a controller.
+type ProcessInfo struct {
+	PID            int
+	StartedAt      time.Time
+	LastExitReason string
 }
 
 type BaseProcessController struct {
@@ -38,6 +46,8 @@ type BaseProcessController struct {
 	restartMu   sync.Mutex
 	restarting  bool
 	onStop      func()
+	startedAt   time.Time
+	lastExit    string
 }
 
 var _ ProcessController = (*BaseProcessController)(nil)
@@ -76,6 +86,7 @@ func (c *BaseProcessController) SetupCmd(cmd *exec.Cmd) error {
 
 	c.cmdMu.Lock()
 	c.Cmd = cmd
+	c.startedAt = time.Now()
 	c.cmdMu.Unlock()
 
 	c.logger.Info("process started")
@@ -114,7 +125,7 @@ func (c *BaseProcessController) Reload(signal syscall.Signal) error {
 	c.cmdMu.Lock()
 	defer c.cmdMu.Unlock()
 	if c.Cmd == nil || c.Cmd.Process == nil {
-		return fmt.Errorf("process not running")
+		return ErrProcessNotRunning
 	}
 	if err := c.Cmd.Process.Signal(signal); err != nil {
 		return fmt.Errorf("failed to reload process: %w", err)
@@ -145,11 +156,25 @@ func (c *BaseProcessController) Stop() error {
 	if c.Cmd == cmdCopy {
 		c.Cmd = nil
 	}
+	c.lastExit = "stopped"
 	c.cmdMu.Unlock()
 	c.logger.Info("process stopped")
 	return nil
 }
 
+func (c *BaseProcessController) ProcessInfo() ProcessInfo {
+	c.cmdMu.Lock()
+	defer c.cmdMu.Unlock()
+	info := ProcessInfo{
+		StartedAt:      c.startedAt,
+		LastExitReason: c.lastExit,
+	}
+	if c.Cmd != nil && c.Cmd.Process != nil {
+		info.PID = c.Cmd.Process.Pid
+	}
+	return info
+}
+
 func (c *BaseProcessController) GetBuffer() []string {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -232,11 +257,15 @@ func (c *BaseProcessController) captureProcessLogs(stdout, stderr io.Reader) {
 	cmdCopy := c.Cmd
 	c.cmdMu.Unlock()
 	if cmdCopy != nil {
-		cmdCopy.Wait()
+		exitReason := "exited"
+		if err := cmdCopy.Wait(); err != nil {
+			exitReason = err.Error()
+		}
 		c.cmdMu.Lock()
 		if c.Cmd == cmdCopy {
 			c.Cmd = nil
 		}
+		c.lastExit = exitReason
 		c.cmdMu.Unlock()
 	}
 	c.logger.Warn("process stopped/died")
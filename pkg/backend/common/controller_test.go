@@ -642,3 +642,77 @@ func TestProcessController_OnStopCallback_NotCalledWhenNil(t *testing.T) {
 		_, ok = <-ch
 	}
 }
+
+func TestProcessController_ProcessInfo_NotStarted(t *testing.T) {
+	b := NewProcessController(logging.NewStdLogger())
+
+	info := b.ProcessInfo()
+	if info.PID != 0 {
+		t.Errorf("expected no pid, got %d", info.PID)
+	}
+	if !info.StartedAt.IsZero() {
+		t.Errorf("expected zero start time, got %v", info.StartedAt)
+	}
+	if info.LastExitReason != "" {
+		t.Errorf("expected empty exit reason, got %q", info.LastExitReason)
+	}
+}
+
+func TestProcessController_ProcessInfo_Running(t *testing.T) {
+	b := NewProcessController(logging.NewStdLogger())
+	defer func() {
+		if b.IsRunning() {
+			if err := b.Stop(); err != nil {
+				t.Errorf("Cleanup Stop failed: %v", err)
+			}
+		}
+	}()
+
+	before := time.Now()
+	if err := b.SetupCmd(exec.Command("sh", "-c", "sleep 1")); err != nil {
+		t.Fatalf("SetupCmd failed: %v", err)
+	}
+
+	info := b.ProcessInfo()
+	if info.PID == 0 {
+		t.Error("expected pid to be set")
+	}
+	if info.StartedAt.Before(before) {
+		t.Errorf("expected start time after %v, got %v", before, info.StartedAt)
+	}
+}
+
+func TestProcessController_ProcessInfo_ExitReason(t *testing.T) {
+	b := NewProcessController(logging.NewStdLogger())
+	ch := b.SubscribeLogs(context.Background())
+
+	if err := b.SetupCmd(exec.Command("sh", "-c", "exit 3")); err != nil {
+		t.Fatalf("SetupCmd failed: %v", err)
+	}
+
+	for range ch {
+	}
+
+	info := b.ProcessInfo()
+	if info.PID != 0 {
+		t.Errorf("expected no pid after exit, got %d", info.PID)
+	}
+	if info.LastExitReason != "exit status 3" {
+		t.Errorf("expected exit reason %q, got %q", "exit status 3", info.LastExitReason)
+	}
+}
+
+func TestProcessController_ProcessInfo_Stopped(t *testing.T) {
+	b := NewProcessController(logging.NewStdLogger())
+
+	if err := b.SetupCmd(exec.Command("sh", "-c", "sleep 1")); err != nil {
+		t.Fatalf("SetupCmd failed: %v", err)
+	}
+	if err := b.Stop(); err != nil {
+		t.Fatalf("Stop failed: %v", err)
+	}
+
+	if reason := b.ProcessInfo().LastExitReason; reason != "stopped" {
+		t.Errorf("expected exit reason %q, got %q", "stopped", reason)
+	}
+}
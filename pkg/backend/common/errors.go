@@ -2,6 +2,8 @@ package common
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 )
 
 var (
@@ -18,4 +20,41 @@ var (
 	ErrFailedStopRunner         = errors.New("failed to stop runner")
 	ErrFailedToGetVersion       = errors.New("failed to get version")
 	ErrFailedToParseVersion     = errors.New("failed to parse version")
+	ErrInvalidConfig            = errors.New("invalid config")
+	ErrInboundNotFound          = errors.New("inbound not found")
+	ErrInboundAlreadyExists     = errors.New("inbound already exists")
 )
+
+const (
+	ConfigStageParse = "parse"
+	ConfigStageCheck = "check"
+)
+
+// ConfigError is a config rejected by the node while parsing it or by the
+// core's own checker. It matches ErrInvalidConfig.
+type ConfigError struct {
+	Stage    string
+	Messages []string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrInvalidConfig, e.Stage, strings.Join(e.Messages, "; "))
+}
+
+func (e *ConfigError) Unwrap() error {
+	return ErrInvalidConfig
+}
+
+// NewCheckError builds a ConfigError from the output of a core's check mode.
+func NewCheckError(output []byte) *ConfigError {
+	var messages []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			messages = append(messages, line)
+		}
+	}
+	if len(messages) == 0 {
+		messages = []string{"config check failed"}
+	}
+	return &ConfigError{Stage: ConfigStageCheck, Messages: messages}
+}
@@ -0,0 +1,27 @@
+package common
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewCheckError(t *testing.T) {
+	err := NewCheckError([]byte("FATAL first\n\n  second  \n"))
+
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Errorf("expected error to match ErrInvalidConfig")
+	}
+	if err.Stage != ConfigStageCheck {
+		t.Errorf("expected stage %q, got %q", ConfigStageCheck, err.Stage)
+	}
+	if len(err.Messages) != 2 || err.Messages[0] != "FATAL first" || err.Messages[1] != "second" {
+		t.Errorf("unexpected messages: %q", err.Messages)
+	}
+}
+
+func TestNewCheckError_EmptyOutput(t *testing.T) {
+	err := NewCheckError(nil)
+	if len(err.Messages) != 1 {
+		t.Errorf("expected a placeholder message, got %q", err.Messages)
+	}
+}
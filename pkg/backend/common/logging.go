@@ -0,0 +1,52 @@
+package common
+
+import (
+	"context"
+
+	"github.com/highlight-apps/node-backend/logging"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the ID of the request a
+// backend call serves.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored in ctx, or an empty string.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// ContextLogger returns logger tagged with the request ID of ctx, so backend
+// logs can be matched with the call that caused them.
+func ContextLogger(ctx context.Context, logger logging.Logger) logging.Logger {
+	id := RequestID(ctx)
+	if id == "" {
+		return logger
+	}
+	return &requestLogger{Logger: logger, prefix: "request_id=" + id + " "}
+}
+
+type requestLogger struct {
+	logging.Logger
+	prefix string
+}
+
+func (l *requestLogger) Debug(args ...any) {
+	l.Logger.Debug(append([]any{l.prefix}, args...)...)
+}
+
+func (l *requestLogger) Info(args ...any) {
+	l.Logger.Info(append([]any{l.prefix}, args...)...)
+}
+
+func (l *requestLogger) Warn(args ...any) {
+	l.Logger.Warn(append([]any{l.prefix}, args...)...)
+}
+
+func (l *requestLogger) Error(args ...any) {
+	l.Logger.Error(append([]any{l.prefix}, args...)...)
+}
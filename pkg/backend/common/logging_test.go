@@ -0,0 +1,49 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) record(level string, args []any) {
+	l.lines = append(l.lines, level+" "+fmt.Sprint(args...))
+}
+
+func (l *recordingLogger) Debug(args ...any) { l.record("debug", args) }
+func (l *recordingLogger) Info(args ...any)  { l.record("info", args) }
+func (l *recordingLogger) Warn(args ...any)  { l.record("warn", args) }
+func (l *recordingLogger) Error(args ...any) { l.record("error", args) }
+
+func TestContextLogger(t *testing.T) {
+	logger := &recordingLogger{}
+	if got := ContextLogger(context.Background(), logger); got != logger {
+		t.Errorf("expected the logger itself without a request ID, got %v", got)
+	}
+
+	ctx := WithRequestID(context.Background(), "req-1")
+	if id := RequestID(ctx); id != "req-1" {
+		t.Errorf("expected request ID req-1, got %q", id)
+	}
+
+	tagged := ContextLogger(ctx, logger)
+	tagged.Debug("checking users")
+	tagged.Info("reloaded")
+	tagged.Warn("slow reload")
+	tagged.Error("failed to reload: ", "boom")
+
+	want := []string{
+		"debug request_id=req-1 checking users",
+		"info request_id=req-1 reloaded",
+		"warn request_id=req-1 slow reload",
+		"error request_id=req-1 failed to reload: boom",
+	}
+	if !reflect.DeepEqual(logger.lines, want) {
+		t.Errorf("expected %q, got %q", want, logger.lines)
+	}
+}
@@ -0,0 +1,31 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var procPath = "/proc"
+
+// ReadProcessRSS returns the resident set size of a process in bytes.
+func ReadProcessRSS(pid int) (uint64, error) {
+	data, err := os.ReadFile(filepath.Join(procPath, strconv.Itoa(pid), "statm"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read statm: %w", err)
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unexpected statm format: %q", string(data))
+	}
+
+	pages, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse resident pages: %w", err)
+	}
+
+	return pages * uint64(os.Getpagesize()), nil
+}
@@ -0,0 +1,69 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReadProcessRSS_Self(t *testing.T) {
+	if _, err := os.Stat("/proc/self/statm"); err != nil {
+		t.Skip("procfs not available")
+	}
+
+	rss, err := ReadProcessRSS(os.Getpid())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if rss == 0 {
+		t.Error("expected non-zero rss")
+	}
+}
+
+func TestReadProcessRSS_Parse(t *testing.T) {
+	orig := procPath
+	defer func() { procPath = orig }()
+	procPath = t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(procPath, "42"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(procPath, "42", "statm"), []byte("1000 25 10 1 0 100 0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rss, err := ReadProcessRSS(42)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if want := uint64(25 * os.Getpagesize()); rss != want {
+		t.Errorf("expected rss %d, got %d", want, rss)
+	}
+}
+
+func TestReadProcessRSS_NotFound(t *testing.T) {
+	orig := procPath
+	defer func() { procPath = orig }()
+	procPath = t.TempDir()
+
+	if _, err := ReadProcessRSS(42); err == nil {
+		t.Error("expected error for missing process")
+	}
+}
+
+func TestReadProcessRSS_InvalidFormat(t *testing.T) {
+	orig := procPath
+	defer func() { procPath = orig }()
+	procPath = t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(procPath, "42"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(procPath, "42", "statm"), []byte("1000"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadProcessRSS(42); err == nil {
+		t.Error("expected error for malformed statm")
+	}
+}
@@ -12,10 +12,12 @@ type Runner interface {
 	Start(config string) error
 	IsRunning() bool
 	Restart(config string) error
-	Reload() error
+	Reload(config string) error
+	Check(config string) error
 	Stop() error
 	SubscribeLogs(ctx context.Context) <-chan string
 	GetBuffer() []string
+	ProcessInfo() ProcessInfo
 }
 
 type BaseRunner struct {
@@ -55,7 +57,11 @@ func (b *BaseRunner) Restart(config string) error {
 	panic("not implemented")
 }
 
-func (b *BaseRunner) Reload() error {
+func (b *BaseRunner) Reload(config string) error {
+	panic("not implemented")
+}
+
+func (b *BaseRunner) Check(config string) error {
 	panic("not implemented")
 }
 
@@ -71,6 +77,10 @@ func (b *BaseRunner) GetBuffer() []string {
 	return b.Controller.GetBuffer()
 }
 
+func (b *BaseRunner) ProcessInfo() ProcessInfo {
+	return b.Controller.ProcessInfo()
+}
+
 func (b *BaseRunner) TriggerStopEvent() {
 	b.stopEventOnce.Do(func() {
 		b.stopEventMu.Lock()
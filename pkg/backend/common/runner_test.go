@@ -18,6 +18,7 @@ type mockController struct {
 	buffer        []string
 	onStopHandler func()
 	logsChan      chan string
+	processInfo   ProcessInfo
 }
 
 func (m *mockController) SetupCmd(cmd *exec.Cmd) error {
@@ -63,6 +64,10 @@ func (m *mockController) SetOnStop(fn func()) {
 	m.onStopHandler = fn
 }
 
+func (m *mockController) ProcessInfo() ProcessInfo {
+	return m.processInfo
+}
+
 func TestNewBaseRunner(t *testing.T) {
 	logger := logging.NewStdLogger()
 	controller := &mockController{}
@@ -160,7 +165,7 @@ func TestBaseRunner_Reload_Panics(t *testing.T) {
 		}
 	}()
 
-	runner.Reload()
+	runner.Reload("config")
 }
 
 func TestBaseRunner_Stop(t *testing.T) {
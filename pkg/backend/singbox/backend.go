@@ -1,13 +1,20 @@
 package singbox
 
 import (
+	"cmp"
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/highlight-apps/node-backend/backend/common"
@@ -15,36 +22,46 @@ import (
 	"github.com/highlight-apps/node-backend/config"
 	"github.com/highlight-apps/node-backend/logging"
 	"github.com/highlight-apps/node-backend/storage"
-	"github.com/highlight-apps/node-backend/utils"
 )
 
 var _ common.VPNBackend = (*SingBoxBackend)(nil)
 
+// clashRetryDelay is the first delay between attempts to reach the clash API
+// after a reload. It doubles with every attempt.
+var clashRetryDelay = 50 * time.Millisecond
+
 type SingBoxBackend struct {
+	name                    string
 	config                  *SingBoxConfig
 	configUpdateEvent       chan struct{}
 	inboundTags             map[string]bool
 	inbounds                []models.Inbound
 	api                     *SingBoxAPI
+	clashAPI                *ClashAPI
 	runner                  *SingboxRunner
 	storage                 storage.BaseStorage
 	configPath              string
 	fullConfigPath          string
 	restartMutex            sync.Mutex
 	configModificationMutex sync.Mutex
+	pendingTraffic          map[int64]*common.UserTraffic
+	restarts                atomic.Int64
+	closed                  atomic.Bool
 	logger                  logging.Logger
 }
 
-func NewSingBoxBackend(executablePath, configPath string, store storage.BaseStorage, logger logging.Logger) (*SingBoxBackend, error) {
-	runner, err := NewSingboxRunner(executablePath, logger)
+func NewSingBoxBackend(name, executablePath, assetsPath, configPath string, store storage.BaseStorage, logger logging.Logger) (*SingBoxBackend, error) {
+	runner, err := NewSingboxRunner(executablePath, assetsPath, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create runner: %w", err)
 	}
 
 	backend := &SingBoxBackend{
+		name:              name,
 		configUpdateEvent: make(chan struct{}, 1),
 		inboundTags:       make(map[string]bool),
 		inbounds:          make([]models.Inbound, 0),
+		pendingTraffic:    make(map[int64]*common.UserTraffic),
 		runner:            runner,
 		storage:           store,
 		configPath:        configPath,
@@ -58,12 +75,16 @@ func NewSingBoxBackend(executablePath, configPath string, store storage.BaseStor
 	return backend, nil
 }
 
+func (s *SingBoxBackend) Name() string {
+	return s.name
+}
+
 func (s *SingBoxBackend) BackendType() string {
 	return "sing-box"
 }
 
 func (s *SingBoxBackend) ConfigFormat() int {
-	return 1
+	return common.ConfigFormatJSON
 }
 
 func (s *SingBoxBackend) Version() (string, error) {
@@ -78,6 +99,11 @@ func (s *SingBoxBackend) ContainsTag(tag string) bool {
 	return s.inboundTags[tag]
 }
 
+// log returns the backend logger tagged with the request ID of ctx.
+func (s *SingBoxBackend) log(ctx context.Context) logging.Logger {
+	return common.ContextLogger(ctx, s.logger)
+}
+
 func (s *SingBoxBackend) userUpdateHandler() {
 	interval := time.Duration(config.SingBoxUserModificationInterval) * time.Second
 	ticker := time.NewTicker(interval)
@@ -91,14 +117,7 @@ func (s *SingBoxBackend) userUpdateHandler() {
 			select {
 			case <-s.configUpdateEvent:
 				s.logger.Debug("updating sing-box users")
-				configJSON, err := s.config.ToJSON()
-				if err != nil {
-					s.logger.Error("failed to convert config to JSON:", err)
-					s.configModificationMutex.Unlock()
-					continue
-				}
-				s.saveConfig(configJSON, true)
-				if err := s.runner.Reload(); err != nil {
+				if err := s.reload(context.Background()); err != nil {
 					s.logger.Error("failed to reload runner:", err)
 				}
 			default:
@@ -108,6 +127,18 @@ func (s *SingBoxBackend) userUpdateHandler() {
 	}
 }
 
+// reload saves the full config and reloads sing-box with it. The caller must
+// hold configModificationMutex.
+func (s *SingBoxBackend) reload(ctx context.Context) error {
+	configJSON, err := s.config.ToJSON()
+	if err != nil {
+		return fmt.Errorf("failed to convert config to JSON: %w", err)
+	}
+	s.saveConfig(configJSON, true)
+	s.collectTraffic(ctx)
+	return s.runner.Reload(configJSON)
+}
+
 func (s *SingBoxBackend) ListInbounds(ctx context.Context) ([]models.Inbound, error) {
 	return s.inbounds, nil
 }
@@ -148,6 +179,9 @@ func (s *SingBoxBackend) restartOnFailure() {
 	stopEvent := s.runner.StopEvent()
 	for {
 		<-stopEvent
+		if s.closed.Load() {
+			return
+		}
 		if s.restartMutex.TryLock() {
 			s.logger.Debug("Sing-box stopped unexpectedly")
 			s.restartMutex.Unlock()
@@ -155,6 +189,7 @@ func (s *SingBoxBackend) restartOnFailure() {
 				restartInterval := time.Duration(config.SingBoxRestartOnFailureInterval) * time.Second
 				time.Sleep(restartInterval)
 
+				s.restarts.Add(1)
 				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 				if err := s.Start(ctx, nil); err != nil {
 					s.logger.Error("failed to restart sing-box:", err)
@@ -168,51 +203,155 @@ func (s *SingBoxBackend) restartOnFailure() {
 	}
 }
 
-func (s *SingBoxBackend) Start(ctx context.Context, backendConfig any) error {
-	var configStr string
-
-	if backendConfig == nil {
+// configString returns the given config as a string, or the config on disk
+// if none is given.
+func (s *SingBoxBackend) configString(backendConfig any) (string, error) {
+	switch cfg := backendConfig.(type) {
+	case nil:
 		data, err := os.ReadFile(s.configPath)
 		if err != nil {
-			return fmt.Errorf("failed to read config: %w", err)
+			return "", fmt.Errorf("failed to read config: %w", err)
 		}
-		configStr = string(data)
-	} else {
-		switch cfg := backendConfig.(type) {
-		case string:
-			configStr = cfg
-		case []byte:
-			configStr = string(cfg)
-		default:
-			data, err := json.Marshal(cfg)
-			if err != nil {
-				return fmt.Errorf("failed to marshal config: %w", err)
-			}
-			configStr = string(data)
+		return string(data), nil
+	case string:
+		return cfg, nil
+	case []byte:
+		return string(cfg), nil
+	default:
+		data, err := json.Marshal(cfg)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal config: %w", err)
 		}
+		return string(data), nil
+	}
+}
+
+// ValidateConfig parses the config the way Start does and runs it through
+// sing-box check. The running process is not touched.
+func (s *SingBoxBackend) ValidateConfig(ctx context.Context, backendConfig any) error {
+	configStr, err := s.configString(backendConfig)
+	if err != nil {
+		return err
+	}
+
+	config, err := NewSingBoxConfig(configStr, "127.0.0.1", 0)
+	if err != nil {
+		return &common.ConfigError{Stage: common.ConfigStageParse, Messages: []string{err.Error()}}
+	}
+
+	return s.checkConfig(config)
+}
+
+// checkConfig runs the sing-box config itself, rather than the node's
+// wrapper, through sing-box check.
+func (s *SingBoxBackend) checkConfig(config *SingBoxConfig) error {
+	data, err := json.Marshal(config.Data)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	return s.runner.Check(string(data))
+}
+
+// DiffConfig compares the config on disk with a proposed one.
+func (s *SingBoxBackend) DiffConfig(ctx context.Context, backendConfig any) (*common.ConfigDiff, error) {
+	currentStr, err := s.configString(nil)
+	if err != nil {
+		return nil, err
+	}
+	current, err := NewSingBoxConfig(currentStr, "127.0.0.1", 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse current config: %w", err)
+	}
+
+	proposedStr, err := s.configString(backendConfig)
+	if err != nil {
+		return nil, err
+	}
+	proposed, err := NewSingBoxConfig(proposedStr, "127.0.0.1", 0)
+	if err != nil {
+		return nil, &common.ConfigError{Stage: common.ConfigStageParse, Messages: []string{err.Error()}}
+	}
 
+	return DiffConfigs(current, proposed), nil
+}
+
+func (s *SingBoxBackend) Start(ctx context.Context, backendConfig any) error {
+	config, savedConfig, err := s.loadConfig(backendConfig)
+	if err != nil {
+		return err
+	}
+	return s.start(ctx, config, savedConfig)
+}
+
+// loadConfig parses the given config, or the one on disk when it is nil, and
+// assigns it the API ports. A new config is also run through sing-box check
+// and returned formatted for saving, so nothing is touched until it is known
+// to be valid.
+func (s *SingBoxBackend) loadConfig(backendConfig any) (*SingBoxConfig, string, error) {
+	configStr, err := s.configString(backendConfig)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var savedConfig string
+	if backendConfig != nil {
 		var prettyConfig map[string]any
 		if err := json.Unmarshal([]byte(configStr), &prettyConfig); err != nil {
-			return fmt.Errorf("failed to parse config JSON: %w", err)
+			return nil, "", fmt.Errorf("%w: failed to parse config JSON: %v", common.ErrInvalidConfig, err)
 		}
 
 		prettyData, err := json.MarshalIndent(prettyConfig, "", "  ")
 		if err != nil {
-			return fmt.Errorf("failed to format config: %w", err)
-		}
-
-		if err := s.saveConfig(string(prettyData), false); err != nil {
-			return fmt.Errorf("failed to save config: %w", err)
+			return nil, "", fmt.Errorf("failed to format config: %w", err)
 		}
 
 		configStr = string(prettyData)
+		savedConfig = configStr
+	}
+
+	apiPort, err := findFreePort()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to find port for API: %w", err)
 	}
 
-	config, err := NewSingBoxConfig(configStr, "127.0.0.1", 8081)
+	clashAPIPort, err := findFreePort()
 	if err != nil {
-		return fmt.Errorf("failed to create config: %w", err)
+		return nil, "", fmt.Errorf("failed to find port for clash API: %w", err)
+	}
+	clashSecret, err := randomSecret()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate clash API secret: %w", err)
+	}
+
+	config, err := NewSingBoxConfig(configStr, "127.0.0.1", apiPort)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: failed to create config: %v", common.ErrInvalidConfig, err)
+	}
+
+	if backendConfig != nil {
+		if err := s.checkConfig(config); err != nil {
+			return nil, "", err
+		}
 	}
 
+	config.EnableClashAPI(clashAPIPort, clashSecret)
+	return config, savedConfig, nil
+}
+
+// start runs a config returned by loadConfig, saving it first when it is new.
+// The config and API clients are swapped under configModificationMutex, since
+// traffic collection reads them at any time.
+func (s *SingBoxBackend) start(ctx context.Context, config *SingBoxConfig, savedConfig string) error {
+	if savedConfig != "" {
+		if err := s.saveConfig(savedConfig, false); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+	}
+
+	s.configModificationMutex.Lock()
+	defer s.configModificationMutex.Unlock()
+
 	s.config = config
 	s.inboundTags = make(map[string]bool)
 	s.inbounds = config.ListInbounds()
@@ -243,18 +382,24 @@ func (s *SingBoxBackend) Start(ctx context.Context, backendConfig any) error {
 		return fmt.Errorf("failed to create API client: %w", err)
 	}
 	s.api = api
+	s.clashAPI = NewClashAPI("127.0.0.1", config.ClashApiPort, config.ClashSecret)
 
 	return s.runner.Start(configJSON)
 }
 
 func (s *SingBoxBackend) stop(ctx context.Context) error {
+	s.configModificationMutex.Lock()
+	defer s.configModificationMutex.Unlock()
+
+	s.collectTraffic(ctx)
+
 	if err := s.runner.Stop(); err != nil {
 		return fmt.Errorf("failed to stop runner: %w", err)
 	}
 
 	for _, inbound := range s.inbounds {
 		if err := s.storage.RemoveInbound(inbound); err != nil {
-			s.logger.Error("failed to remove inbound:", inbound.Tag, err)
+			s.log(ctx).Error("failed to remove inbound:", inbound.Tag, err)
 		}
 	}
 
@@ -264,23 +409,50 @@ func (s *SingBoxBackend) stop(ctx context.Context) error {
 	return nil
 }
 
+func (s *SingBoxBackend) Stop(ctx context.Context) error {
+	s.restartMutex.Lock()
+	defer s.restartMutex.Unlock()
+
+	s.closed.Store(true)
+	if !s.runner.IsRunning() {
+		return nil
+	}
+	return s.stop(ctx)
+}
+
 func (s *SingBoxBackend) Restart(ctx context.Context, backendConfig any) error {
 	s.restartMutex.Lock()
 	defer s.restartMutex.Unlock()
 
 	if isEmpty(backendConfig) {
+		s.configModificationMutex.Lock()
+		// A backend that never started has no config to restart with.
+		if s.config == nil {
+			s.configModificationMutex.Unlock()
+			return common.ErrConfigNotSet
+		}
+		s.collectTraffic(ctx)
 		configJSON, err := s.config.ToJSON()
+		s.configModificationMutex.Unlock()
+
 		if err != nil {
 			return fmt.Errorf("failed to get current config: %w", err)
 		}
 		return s.runner.Restart(configJSON)
 	}
 
+	// A config that fails to parse or check leaves the running core and the
+	// config on disk as they are.
+	config, savedConfig, err := s.loadConfig(backendConfig)
+	if err != nil {
+		return err
+	}
+
 	if err := s.stop(ctx); err != nil {
 		return fmt.Errorf("failed to stop during restart: %w", err)
 	}
 
-	return s.Start(ctx, backendConfig)
+	return s.start(ctx, config, savedConfig)
 }
 
 func (s *SingBoxBackend) AddUser(ctx context.Context, user models.User, inbound models.Inbound) error {
@@ -315,8 +487,11 @@ func (s *SingBoxBackend) RemoveUser(ctx context.Context, user models.User, inbou
 	return nil
 }
 
+// GetLogs subscribes to the logs before returning, so the channel is closed
+// by a process exit that happens any time after the call.
 func (s *SingBoxBackend) GetLogs(ctx context.Context, includeBuffer bool) (<-chan string, error) {
 	logChan := make(chan string, 100)
+	streamChan := s.runner.SubscribeLogs(ctx)
 
 	go func() {
 		defer close(logChan)
@@ -332,7 +507,6 @@ func (s *SingBoxBackend) GetLogs(ctx context.Context, includeBuffer bool) (<-cha
 			}
 		}
 
-		streamChan := s.runner.SubscribeLogs(ctx)
 		for line := range streamChan {
 			select {
 			case logChan <- line:
@@ -346,8 +520,45 @@ func (s *SingBoxBackend) GetLogs(ctx context.Context, includeBuffer bool) (<-cha
 }
 
 func (s *SingBoxBackend) GetUsages(ctx context.Context) (any, error) {
+	traffic, err := s.GetUserTraffic(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[int64]int64)
+	for _, t := range traffic {
+		stats[t.UID] += t.Uplink + t.Downlink
+	}
+
+	return stats, nil
+}
+
+// GetUserTraffic returns the traffic counted since the previous call,
+// including traffic collected before core reloads and restarts.
+func (s *SingBoxBackend) GetUserTraffic(ctx context.Context) ([]common.UserTraffic, error) {
+	s.configModificationMutex.Lock()
+	defer s.configModificationMutex.Unlock()
+
+	s.collectTraffic(ctx)
+
+	result := make([]common.UserTraffic, 0, len(s.pendingTraffic))
+	for _, t := range s.pendingTraffic {
+		result = append(result, *t)
+	}
+	slices.SortFunc(result, func(a, b common.UserTraffic) int {
+		return cmp.Compare(a.UID, b.UID)
+	})
+	s.pendingTraffic = make(map[int64]*common.UserTraffic)
+
+	return result, nil
+}
+
+// collectTraffic moves the counters of the core into pendingTraffic, since
+// the core resets them on reload and restart. The caller must hold
+// configModificationMutex.
+func (s *SingBoxBackend) collectTraffic(ctx context.Context) {
 	if s.api == nil {
-		return make(map[int64]int64), nil
+		return
 	}
 
 	timeoutCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
@@ -355,16 +566,79 @@ func (s *SingBoxBackend) GetUsages(ctx context.Context) (any, error) {
 
 	apiStats, err := s.api.GetUsersStats(timeoutCtx, true)
 	if err != nil {
-		s.logger.Error("failed to get stats:", err)
-		return make(map[int64]int64), nil
+		s.log(ctx).Error("failed to get stats:", err)
+		return
 	}
 
-	stats := make(map[int64]int64)
 	for _, stat := range apiStats {
 		parts := strings.Split(stat.Name, ".")
-		if len(parts) > 0 {
-			if uid, err := strconv.ParseInt(parts[0], 10, 64); err == nil {
-				stats[uid] += int64(stat.Value)
+		uid, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		t, ok := s.pendingTraffic[uid]
+		if !ok {
+			t = &common.UserTraffic{UID: uid}
+			if s.config != nil {
+				if tags := s.config.UserInboundTags(stat.Name); len(tags) == 1 {
+					t.InboundTag = tags[0]
+				}
+			}
+			s.pendingTraffic[uid] = t
+		}
+
+		switch stat.Link {
+		case "uplink":
+			t.Uplink += stat.Value
+		case "downlink":
+			t.Downlink += stat.Value
+		}
+	}
+}
+
+func (s *SingBoxBackend) GetStats(ctx context.Context) (*common.BackendStats, error) {
+	info := s.runner.ProcessInfo()
+	stats := &common.BackendStats{
+		Running:        s.runner.IsRunning(),
+		Restarts:       s.restarts.Load(),
+		LastExitReason: info.LastExitReason,
+	}
+
+	if !stats.Running {
+		return stats, nil
+	}
+
+	stats.PID = info.PID
+	if !info.StartedAt.IsZero() {
+		stats.Uptime = time.Since(info.StartedAt)
+	}
+
+	if rss, err := common.ReadProcessRSS(info.PID); err == nil {
+		stats.RSS = rss
+	} else {
+		s.log(ctx).Debug("failed to read sing-box rss:", err)
+	}
+
+	if s.api != nil {
+		timeoutCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+		defer cancel()
+
+		sysStats, err := s.api.GetSysStats(timeoutCtx)
+		if err != nil {
+			s.log(ctx).Error("failed to get sys stats:", err)
+		} else {
+			stats.Runtime = &common.RuntimeStats{
+				NumGoroutine: sysStats.NumGoroutine,
+				NumGC:        sysStats.NumGC,
+				Alloc:        sysStats.Alloc,
+				TotalAlloc:   sysStats.TotalAlloc,
+				Sys:          sysStats.Sys,
+				Mallocs:      sysStats.Mallocs,
+				Frees:        sysStats.Frees,
+				LiveObjects:  sysStats.LiveObjects,
+				PauseTotalNs: sysStats.PauseTotalNs,
+				Uptime:       sysStats.Uptime,
 			}
 		}
 	}
@@ -372,6 +646,112 @@ func (s *SingBoxBackend) GetUsages(ctx context.Context) (any, error) {
 	return stats, nil
 }
 
+// ListConnections returns the connections sing-box is proxying, as reported
+// by its clash API.
+func (s *SingBoxBackend) ListConnections(ctx context.Context) ([]common.Connection, error) {
+	if s.clashAPI == nil {
+		return nil, common.ErrProcessNotRunning
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	response, err := s.clashAPI.GetConnections(timeoutCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connections: %w", err)
+	}
+
+	connections := make([]common.Connection, 0, len(response.Connections))
+	for _, connection := range response.Connections {
+		connections = append(connections, common.Connection{
+			ID:          connection.ID,
+			User:        connection.UserIdentifier(),
+			InboundTag:  connection.InboundTag(),
+			Network:     connection.Metadata.Network,
+			Source:      connection.Source(),
+			Destination: connection.Destination(),
+			Upload:      connection.Upload,
+			Download:    connection.Download,
+			Start:       connection.Start,
+		})
+	}
+	return connections, nil
+}
+
+// DisconnectUser closes the connections of a user and returns how many were
+// closed. Pending user changes are applied first, so a removed user can't
+// connect again until the next reload.
+func (s *SingBoxBackend) DisconnectUser(ctx context.Context, uid int64) (int, error) {
+	s.configModificationMutex.Lock()
+	clashAPI := s.clashAPI
+	if clashAPI == nil {
+		s.configModificationMutex.Unlock()
+		return 0, common.ErrProcessNotRunning
+	}
+
+	reloaded := false
+	select {
+	case <-s.configUpdateEvent:
+		if err := s.reload(ctx); err != nil {
+			s.log(ctx).Error("failed to reload runner:", err)
+		} else {
+			reloaded = true
+		}
+	default:
+	}
+	s.configModificationMutex.Unlock()
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	response, err := clashAPI.GetConnections(timeoutCtx)
+	// A reload restarts the clash API, which may not be listening yet.
+	for delay := clashRetryDelay; err != nil && reloaded && timeoutCtx.Err() == nil; delay *= 2 {
+		select {
+		case <-timeoutCtx.Done():
+		case <-time.After(delay):
+			response, err = clashAPI.GetConnections(timeoutCtx)
+		}
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get connections: %w", err)
+	}
+
+	closed := 0
+	var errs []error
+	for _, connection := range response.Connections {
+		id, _, _ := strings.Cut(connection.UserIdentifier(), ".")
+		if id != strconv.FormatInt(uid, 10) {
+			continue
+		}
+		if err := clashAPI.CloseConnection(timeoutCtx, connection.ID); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close connection %s: %w", connection.ID, err))
+			continue
+		}
+		closed++
+	}
+	return closed, errors.Join(errs...)
+}
+
+// findFreePort returns a loopback port for the core API, so several sing-box
+// instances can run side by side.
+func findFreePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+func randomSecret() (string, error) {
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
 func isEmpty(value any) bool {
 	if value == nil {
 		return true
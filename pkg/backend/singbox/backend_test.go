@@ -1 +1,393 @@
 package singbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/highlight-apps/node-backend/backend/common"
+	"github.com/highlight-apps/node-backend/backend/common/models"
+	"github.com/highlight-apps/node-backend/backend/singbox/api"
+	"google.golang.org/grpc"
+)
+
+func TestSingBoxBackend_RestartInvalidConfig(t *testing.T) {
+	origExec := execCommand
+	defer func() { execCommand = origExec }()
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		return exec.Command("sh", "-c", "echo 'FATAL[0000] decode config: unknown field'; exit 1")
+	}
+
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{name: "malformed JSON", config: `{"inbounds": [`},
+		{name: "failed check", config: `{"inbounds": [{"type": "vless", "tag": "other-in", "listen_port": 8443}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &inboundsTestStorage{
+				MockStorage: MockStorage{inbounds: map[string]*models.Inbound{"vless-in": {Tag: "vless-in"}}},
+				users:       map[int64]models.User{},
+			}
+			backend := newInboundsTestBackend(t, store)
+
+			err := backend.Restart(context.Background(), tt.config)
+			if !errors.Is(err, common.ErrInvalidConfig) {
+				t.Fatalf("expected ErrInvalidConfig, got %v", err)
+			}
+
+			data, err := os.ReadFile(backend.configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != inboundsTestConfig {
+				t.Errorf("expected config on disk to be untouched, got %s", data)
+			}
+			if !backend.ContainsTag("vless-in") {
+				t.Error("expected backend to keep its inbounds")
+			}
+			if _, ok := store.inbounds["vless-in"]; !ok {
+				t.Error("expected inbound to stay registered in storage")
+			}
+		})
+	}
+}
+
+func TestSingBoxBackend_RestartWithoutConfig(t *testing.T) {
+	origExec := execCommand
+	defer func() { execCommand = origExec }()
+	started := false
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		started = true
+		return exec.Command("true")
+	}
+
+	runner, err := NewSingboxRunner("sing-box", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &SingBoxBackend{runner: runner}
+
+	for _, config := range []any{nil, ""} {
+		if err := backend.Restart(context.Background(), config); !errors.Is(err, common.ErrConfigNotSet) {
+			t.Errorf("expected ErrConfigNotSet for config %q, got %v", config, err)
+		}
+	}
+	if started {
+		t.Error("expected no core to be started without a config")
+	}
+}
+
+// trafficTestCore counts user traffic like the sing-box stats service, which
+// resets the counters it reports when queried with reset.
+type trafficTestCore struct {
+	mu       sync.Mutex
+	counters map[string]int64
+}
+
+func (c *trafficTestCore) add(user string, uplink, downlink int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counters["user>>>"+user+">>>traffic>>>uplink"] += uplink
+	c.counters["user>>>"+user+">>>traffic>>>downlink"] += downlink
+}
+
+func (c *trafficTestCore) statsAPI() *SingBoxAPI {
+	statsAPI := createMockAPI()
+	statsAPI.client = &MockStatsServiceClient{
+		queryStatsFunc: func(ctx context.Context, req *api.QueryStatsRequest, opts ...grpc.CallOption) (*api.QueryStatsResponse, error) {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			var stats []*api.Stat
+			for name, value := range c.counters {
+				if strings.HasPrefix(name, req.Pattern) {
+					stats = append(stats, &api.Stat{Name: name, Value: value})
+				}
+			}
+			if req.Reset_ {
+				clear(c.counters)
+			}
+			return &api.QueryStatsResponse{Stat: stats}, nil
+		},
+	}
+	return statsAPI
+}
+
+// newRunningTestBackend returns a running backend whose stats come from the
+// returned core. The core is a shell process that prints the config file it
+// was started with on SIGHUP. User 1 is on vless-in; user 2 is not in the
+// config.
+func newRunningTestBackend(t *testing.T) (*SingBoxBackend, *trafficTestCore) {
+	t.Helper()
+
+	origExec := execCommand
+	t.Cleanup(func() { execCommand = origExec })
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		if args[0] == "check" {
+			return exec.Command("true")
+		}
+		configPath := args[len(args)-1]
+		return exec.Command("sh", "-c", `trap 'cat "$0"; echo' HUP; echo ready; while :; do sleep 0.05; done`, configPath)
+	}
+
+	store := &inboundsTestStorage{
+		MockStorage: MockStorage{inbounds: map[string]*models.Inbound{}},
+		users:       map[int64]models.User{},
+	}
+	backend := newInboundsTestBackend(t, store)
+	user := models.User{ID: 1, Username: "alice", Key: "alice-key"}
+	if err := backend.config.AppendUser(user, models.Inbound{Tag: "vless-in", Protocol: "vless"}); err != nil {
+		t.Fatal(err)
+	}
+
+	core := &trafficTestCore{counters: map[string]int64{}}
+	backend.api = core.statsAPI()
+
+	configJSON, err := backend.config.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := backend.runner.Start(configJSON); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { backend.runner.Stop() })
+
+	// A SIGHUP before the trap is set would kill the core.
+	deadline := time.Now().Add(2 * time.Second)
+	for !slices.Contains(backend.runner.GetBuffer(), "ready") {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the core to start")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return backend, core
+}
+
+func TestSingBoxBackend_GetUserTraffic(t *testing.T) {
+	backend, core := newRunningTestBackend(t)
+	core.add("1.alice", 10, 200)
+	core.add("2.bob", 30, 0)
+	core.add("1.alice", 5, 0)
+
+	traffic, err := backend.GetUserTraffic(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get traffic: %v", err)
+	}
+	want := []common.UserTraffic{
+		{UID: 1, InboundTag: "vless-in", Uplink: 15, Downlink: 200},
+		{UID: 2, Uplink: 30},
+	}
+	if !reflect.DeepEqual(traffic, want) {
+		t.Errorf("expected %v, got %v", want, traffic)
+	}
+
+	traffic, err = backend.GetUserTraffic(context.Background())
+	if err != nil {
+		t.Fatalf("failed to get traffic: %v", err)
+	}
+	if len(traffic) != 0 {
+		t.Errorf("expected reported traffic to be reset, got %v", traffic)
+	}
+}
+
+func TestSingBoxBackend_TrafficAcrossReload(t *testing.T) {
+	tests := []struct {
+		name   string
+		action func(backend *SingBoxBackend) error
+		// carried is whether the core keeps counting into the same stats
+		// service after the action.
+		carried bool
+	}{
+		{
+			name: "reload",
+			action: func(backend *SingBoxBackend) error {
+				backend.configModificationMutex.Lock()
+				defer backend.configModificationMutex.Unlock()
+				return backend.reload(context.Background())
+			},
+			carried: true,
+		},
+		{
+			name: "restart",
+			action: func(backend *SingBoxBackend) error {
+				return backend.Restart(context.Background(), nil)
+			},
+			carried: true,
+		},
+		{
+			name: "restart with config",
+			action: func(backend *SingBoxBackend) error {
+				return backend.Restart(context.Background(), inboundsTestConfig)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, core := newRunningTestBackend(t)
+			core.add("1.alice", 10, 20)
+
+			// Traffic is polled while the action swaps the backend state.
+			var total common.UserTraffic
+			done := make(chan struct{})
+			polled := make(chan struct{})
+			go func() {
+				defer close(polled)
+				for {
+					traffic, _ := backend.GetUserTraffic(context.Background())
+					for _, user := range traffic {
+						total.Uplink += user.Uplink
+						total.Downlink += user.Downlink
+					}
+					select {
+					case <-done:
+						return
+					default:
+					}
+				}
+			}()
+
+			if err := tt.action(backend); err != nil {
+				t.Fatalf("failed to %s: %v", tt.name, err)
+			}
+			close(done)
+			<-polled
+
+			want := common.UserTraffic{Uplink: 10, Downlink: 20}
+			if tt.carried {
+				core.add("1.alice", 1, 2)
+				want = common.UserTraffic{Uplink: 11, Downlink: 22}
+			}
+			traffic, err := backend.GetUserTraffic(context.Background())
+			if err != nil {
+				t.Fatalf("failed to get traffic: %v", err)
+			}
+			for _, user := range traffic {
+				total.Uplink += user.Uplink
+				total.Downlink += user.Downlink
+			}
+			if total != want {
+				t.Errorf("expected total traffic %v, got %v", want, total)
+			}
+		})
+	}
+}
+
+type backendTestLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *backendTestLogger) record(args []any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprint(args...))
+}
+
+func (l *backendTestLogger) Debug(args ...any) { l.record(args) }
+func (l *backendTestLogger) Info(args ...any)  { l.record(args) }
+func (l *backendTestLogger) Warn(args ...any)  { l.record(args) }
+func (l *backendTestLogger) Error(args ...any) { l.record(args) }
+
+func TestSingBoxBackend_LogsRequestID(t *testing.T) {
+	store := &inboundsTestStorage{
+		MockStorage: MockStorage{inbounds: map[string]*models.Inbound{}},
+		users:       map[int64]models.User{},
+	}
+	backend := newInboundsTestBackend(t, store)
+	logger := &backendTestLogger{}
+	backend.logger = logger
+	backend.api = createMockAPI()
+	backend.api.client = &MockStatsServiceClient{
+		queryStatsFunc: func(ctx context.Context, req *api.QueryStatsRequest, opts ...grpc.CallOption) (*api.QueryStatsResponse, error) {
+			return nil, errors.New("stats unavailable")
+		},
+	}
+
+	ctx := common.WithRequestID(context.Background(), "panel-request-1")
+	if _, err := backend.GetUserTraffic(ctx); err != nil {
+		t.Fatalf("failed to get traffic: %v", err)
+	}
+
+	want := "request_id=panel-request-1 failed to get stats:stats unavailable"
+	if !reflect.DeepEqual(logger.lines, []string{want}) {
+		t.Errorf("expected %q, got %q", []string{want}, logger.lines)
+	}
+}
+
+func TestSingBoxBackend_ReloadRewritesCoreConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(backend *SingBoxBackend) error
+		want   string
+	}{
+		{
+			name: "add inbound",
+			change: func(backend *SingBoxBackend) error {
+				_, err := backend.AddInbound(context.Background(), `{"type": "shadowsocks", "tag": "ss-in", "listen_port": 8388, "method": "chacha20-ietf-poly1305"}`)
+				return err
+			},
+			want: `"ss-in"`,
+		},
+		{
+			name: "replace inbound",
+			change: func(backend *SingBoxBackend) error {
+				_, err := backend.ReplaceInbound(context.Background(), `{"type": "vless", "tag": "vless-in", "listen_port": 8443}`)
+				return err
+			},
+			want: "8443",
+		},
+		{
+			name: "pending user change",
+			change: func(backend *SingBoxBackend) error {
+				user := models.User{ID: 2, Username: "bob", Key: "bob-key"}
+				if err := backend.AddUser(context.Background(), user, models.Inbound{Tag: "vless-in", Protocol: "vless"}); err != nil {
+					return err
+				}
+				backend.configModificationMutex.Lock()
+				defer backend.configModificationMutex.Unlock()
+				return backend.reload(context.Background())
+			},
+			want: `"2.bob"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, _ := newRunningTestBackend(t)
+			configPath := backend.runner.configFilePath
+
+			if err := tt.change(backend); err != nil {
+				t.Fatalf("failed to change config: %v", err)
+			}
+
+			data, err := os.ReadFile(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), tt.want) {
+				t.Errorf("expected the config file of the core to contain %s, got %s", tt.want, data)
+			}
+
+			// The core prints the file it reads on reload.
+			deadline := time.Now().Add(2 * time.Second)
+			for !strings.Contains(strings.Join(backend.runner.GetBuffer(), "\n"), tt.want) {
+				if time.Now().After(deadline) {
+					t.Fatalf("expected the core to read %s on reload, got %q", tt.want, backend.runner.GetBuffer())
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+		})
+	}
+}
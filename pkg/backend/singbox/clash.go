@@ -0,0 +1,134 @@
+package singbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ClashConnectionMetadata struct {
+	Network         string `json:"network"`
+	Type            string `json:"type"`
+	SourceIP        string `json:"sourceIP"`
+	SourcePort      string `json:"sourcePort"`
+	DestinationIP   string `json:"destinationIP"`
+	DestinationPort string `json:"destinationPort"`
+	Host            string `json:"host"`
+	User            string `json:"user"`
+	InboundUser     string `json:"inboundUser"`
+}
+
+type ClashConnection struct {
+	ID       string                  `json:"id"`
+	Metadata ClashConnectionMetadata `json:"metadata"`
+	Upload   int64                   `json:"upload"`
+	Download int64                   `json:"download"`
+	Start    time.Time               `json:"start"`
+	Chains   []string                `json:"chains"`
+	Rule     string                  `json:"rule"`
+}
+
+type ClashConnectionsResponse struct {
+	UploadTotal   int64             `json:"uploadTotal"`
+	DownloadTotal int64             `json:"downloadTotal"`
+	Connections   []ClashConnection `json:"connections"`
+}
+
+// UserIdentifier returns the account of the connection, if the core reports
+// one.
+func (c ClashConnection) UserIdentifier() string {
+	if c.Metadata.User != "" {
+		return c.Metadata.User
+	}
+	return c.Metadata.InboundUser
+}
+
+// InboundTag returns the tag of the inbound the connection came through. The
+// core reports the inbound as "type/tag".
+func (c ClashConnection) InboundTag() string {
+	_, tag, found := strings.Cut(c.Metadata.Type, "/")
+	if !found {
+		return ""
+	}
+	return tag
+}
+
+func (c ClashConnection) Source() string {
+	return joinHostPort(c.Metadata.SourceIP, c.Metadata.SourcePort)
+}
+
+// Destination prefers the requested host over the resolved address.
+func (c ClashConnection) Destination() string {
+	host := c.Metadata.Host
+	if host == "" {
+		host = c.Metadata.DestinationIP
+	}
+	return joinHostPort(host, c.Metadata.DestinationPort)
+}
+
+// ClashAPI is a client for the clash_api listener of sing-box.
+type ClashAPI struct {
+	baseURL string
+	secret  string
+	client  *http.Client
+}
+
+func NewClashAPI(address string, port int, secret string) *ClashAPI {
+	return &ClashAPI{
+		baseURL: "http://" + net.JoinHostPort(address, strconv.Itoa(port)),
+		secret:  secret,
+		client:  &http.Client{},
+	}
+}
+
+func (c *ClashAPI) GetConnections(ctx context.Context) (*ClashConnectionsResponse, error) {
+	var response ClashConnectionsResponse
+	if err := c.do(ctx, http.MethodGet, "/connections", &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *ClashAPI) CloseConnection(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/connections/"+url.PathEscape(id), nil)
+}
+
+func (c *ClashAPI) do(ctx context.Context, method, path string, out any) error {
+	request, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if c.secret != "" {
+		request.Header.Set("Authorization", "Bearer "+c.secret)
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("clash api %s %s: %s", method, path, response.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(response.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode clash api response: %w", err)
+	}
+	return nil
+}
+
+func joinHostPort(host, port string) string {
+	if port == "" || port == "0" {
+		return host
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -0,0 +1,259 @@
+package singbox
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/highlight-apps/node-backend/backend/common"
+)
+
+const clashConnectionsResponse = `{
+	"uploadTotal": 300,
+	"downloadTotal": 700,
+	"connections": [
+		{
+			"id": "a1",
+			"metadata": {
+				"network": "tcp",
+				"type": "vless/vless-in",
+				"sourceIP": "203.0.113.5",
+				"sourcePort": "51000",
+				"destinationIP": "93.184.216.34",
+				"destinationPort": "443",
+				"host": "example.com",
+				"user": "1.alice"
+			},
+			"upload": 100,
+			"download": 200,
+			"start": "2024-01-02T03:04:05Z",
+			"chains": ["direct"]
+		},
+		{
+			"id": "b2",
+			"metadata": {
+				"network": "udp",
+				"type": "shadowsocks",
+				"sourceIP": "2001:db8::1",
+				"sourcePort": "4000",
+				"destinationIP": "1.1.1.1",
+				"destinationPort": "53",
+				"host": ""
+			},
+			"upload": 200,
+			"download": 500,
+			"start": "2024-01-02T03:04:05Z"
+		}
+	]
+}`
+
+func newClashTestServer(t *testing.T, handler http.HandlerFunc) *ClashAPI {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	portNumber, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewClashAPI(host, portNumber, "secret")
+}
+
+func TestClashAPI_GetConnections(t *testing.T) {
+	api := newClashTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/connections" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("expected bearer secret, got %q", got)
+		}
+		w.Write([]byte(clashConnectionsResponse))
+	})
+
+	response, err := api.GetConnections(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(response.Connections) != 2 || response.UploadTotal != 300 {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+
+	connection := response.Connections[0]
+	if connection.UserIdentifier() != "1.alice" {
+		t.Errorf("expected user 1.alice, got %q", connection.UserIdentifier())
+	}
+	if connection.InboundTag() != "vless-in" {
+		t.Errorf("expected inbound vless-in, got %q", connection.InboundTag())
+	}
+	if connection.Source() != "203.0.113.5:51000" {
+		t.Errorf("unexpected source %q", connection.Source())
+	}
+	if connection.Destination() != "example.com:443" {
+		t.Errorf("unexpected destination %q", connection.Destination())
+	}
+
+	connection = response.Connections[1]
+	if connection.UserIdentifier() != "" || connection.InboundTag() != "" {
+		t.Errorf("expected no user and inbound, got %q and %q", connection.UserIdentifier(), connection.InboundTag())
+	}
+	if connection.Source() != "[2001:db8::1]:4000" {
+		t.Errorf("unexpected source %q", connection.Source())
+	}
+	if connection.Destination() != "1.1.1.1:53" {
+		t.Errorf("unexpected destination %q", connection.Destination())
+	}
+}
+
+func TestClashAPI_ErrorStatus(t *testing.T) {
+	api := newClashTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	if _, err := api.GetConnections(context.Background()); err == nil {
+		t.Error("expected error for unauthorized response")
+	}
+}
+
+func TestClashConnection_InboundUser(t *testing.T) {
+	connection := ClashConnection{Metadata: ClashConnectionMetadata{InboundUser: "2.bob"}}
+	if connection.UserIdentifier() != "2.bob" {
+		t.Errorf("expected user 2.bob, got %q", connection.UserIdentifier())
+	}
+}
+
+func TestSingBoxBackend_ListConnections(t *testing.T) {
+	backend := &SingBoxBackend{}
+	if _, err := backend.ListConnections(context.Background()); !errors.Is(err, common.ErrProcessNotRunning) {
+		t.Errorf("expected ErrProcessNotRunning, got %v", err)
+	}
+
+	backend.clashAPI = newClashTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(clashConnectionsResponse))
+	})
+
+	connections, err := backend.ListConnections(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(connections) != 2 {
+		t.Fatalf("expected 2 connections, got %d", len(connections))
+	}
+
+	connection := connections[0]
+	if connection.ID != "a1" || connection.User != "1.alice" || connection.InboundTag != "vless-in" {
+		t.Errorf("unexpected connection: %+v", connection)
+	}
+	if connection.Upload != 100 || connection.Download != 200 || connection.Network != "tcp" {
+		t.Errorf("unexpected connection counters: %+v", connection)
+	}
+	if connection.Start.IsZero() {
+		t.Error("expected start time to be parsed")
+	}
+}
+
+func TestSingBoxBackend_DisconnectUser(t *testing.T) {
+	var closed []string
+	backend := &SingBoxBackend{configUpdateEvent: make(chan struct{}, 1)}
+	backend.clashAPI = newClashTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Write([]byte(clashConnectionsResponse))
+		case http.MethodDelete:
+			closed = append(closed, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	count, err := backend.DisconnectUser(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if count != 1 || len(closed) != 1 || closed[0] != "/connections/a1" {
+		t.Errorf("expected connection a1 to be closed, got %d: %v", count, closed)
+	}
+
+	count, err = backend.DisconnectUser(context.Background(), 2)
+	if err != nil || count != 0 {
+		t.Errorf("expected no connections to be closed, got %d: %v", count, err)
+	}
+}
+
+func TestSingBoxBackend_DisconnectUser_CloseError(t *testing.T) {
+	backend := &SingBoxBackend{configUpdateEvent: make(chan struct{}, 1)}
+	backend.clashAPI = newClashTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(clashConnectionsResponse))
+	})
+
+	count, err := backend.DisconnectUser(context.Background(), 1)
+	if err == nil || count != 0 {
+		t.Errorf("expected error and no closed connections, got %d: %v", count, err)
+	}
+}
+
+func TestSingBoxBackend_DisconnectUser_AfterReload(t *testing.T) {
+	tests := []struct {
+		name     string
+		pending  bool
+		attempts int
+		wantErr  bool
+	}{
+		{name: "retried after reload", pending: true, attempts: 3},
+		{name: "not retried without reload", attempts: 1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, _ := newRunningTestBackend(t)
+			backend.configUpdateEvent = make(chan struct{}, 1)
+			if tt.pending {
+				backend.configUpdateEvent <- struct{}{}
+			}
+
+			// The clash API is down for the first two requests, as it is
+			// while the core reloads.
+			var mu sync.Mutex
+			attempts := 0
+			backend.clashAPI = newClashTestServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodDelete {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				mu.Lock()
+				attempts++
+				down := attempts <= 2
+				mu.Unlock()
+				if down {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.Write([]byte(clashConnectionsResponse))
+			})
+
+			count, err := backend.DisconnectUser(context.Background(), 1)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+			} else if err != nil || count != 1 {
+				t.Errorf("expected connection a1 to be closed, got %d: %v", count, err)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("expected %d attempts, got %d", tt.attempts, attempts)
+			}
+		})
+	}
+}
@@ -17,6 +17,8 @@ type SingBoxConfig struct {
 	Data          map[string]any            `json:"data"`
 	ApiHost       string                    `json:"apiHost"`
 	ApiPort       int                       `json:"apiPort"`
+	ClashApiPort  int                       `json:"clashApiPort"`
+	ClashSecret   string                    `json:"clashSecret"`
 	Inbounds      []map[string]any          `json:"inbounds"`
 	InboundsByTag map[string]map[string]any `json:"inboundsByTag"`
 }
@@ -75,6 +77,25 @@ func (c *SingBoxConfig) applyAPI() {
 
 	v2rayAPI["stats"] = stats
 	experimental["v2ray_api"] = v2rayAPI
+
+	if c.ClashApiPort != 0 {
+		clashAPI, hasClashAPI := experimental["clash_api"].(map[string]any)
+		if !hasClashAPI {
+			clashAPI = make(map[string]any)
+		}
+
+		clashAPI["external_controller"] = c.ApiHost + ":" + strconv.Itoa(c.ClashApiPort)
+		clashAPI["secret"] = c.ClashSecret
+		experimental["clash_api"] = clashAPI
+	}
+}
+
+// EnableClashAPI adds a clash_api listener on the API host, which exposes the
+// active connections of the core.
+func (c *SingBoxConfig) EnableClashAPI(port int, secret string) {
+	c.ClashApiPort = port
+	c.ClashSecret = secret
+	c.applyAPI()
 }
 
 func (c *SingBoxConfig) resolveInbounds() {
@@ -319,6 +340,108 @@ func (c *SingBoxConfig) PopUser(user models.User, inbound models.Inbound) error
 	return nil
 }
 
+// HasInbound reports whether the config has an inbound with the tag,
+// supported by the node or not.
+func (c *SingBoxConfig) HasInbound(tag string) bool {
+	return c.inboundConfig(tag) != nil
+}
+
+// inboundConfig returns the config entry of the inbound with the tag, or nil.
+func (c *SingBoxConfig) inboundConfig(tag string) map[string]any {
+	inbounds, _ := c.Data["inbounds"].([]any)
+	for _, item := range inbounds {
+		if inboundMap, ok := item.(map[string]any); ok && inboundMap["tag"] == tag {
+			return inboundMap
+		}
+	}
+	return nil
+}
+
+// SetInbound adds the inbound to the config, replacing the one with the same
+// tag. It reports whether an inbound was replaced.
+func (c *SingBoxConfig) SetInbound(inbound map[string]any) (bool, error) {
+	tag, ok := inbound["tag"].(string)
+	if !ok || tag == "" {
+		return false, fmt.Errorf("inbound has no tag")
+	}
+
+	inbounds, _ := c.Data["inbounds"].([]any)
+	replaced := false
+	for i, item := range inbounds {
+		if inboundMap, ok := item.(map[string]any); ok && inboundMap["tag"] == tag {
+			inbounds[i] = inbound
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		inbounds = append(inbounds, inbound)
+	}
+	c.Data["inbounds"] = inbounds
+
+	c.refreshInbounds()
+	return replaced, nil
+}
+
+// RemoveInbound removes the inbound with the tag. It reports whether the
+// inbound existed.
+func (c *SingBoxConfig) RemoveInbound(tag string) bool {
+	inbounds, _ := c.Data["inbounds"].([]any)
+	for i, item := range inbounds {
+		if inboundMap, ok := item.(map[string]any); ok && inboundMap["tag"] == tag {
+			c.Data["inbounds"] = slices.Delete(inbounds, i, i+1)
+			c.refreshInbounds()
+			return true
+		}
+	}
+	return false
+}
+
+func (c *SingBoxConfig) refreshInbounds() {
+	c.Inbounds = make([]map[string]any, 0)
+	c.InboundsByTag = make(map[string]map[string]any)
+	c.resolveInbounds()
+}
+
+// UserInboundTags returns the tags of the inbounds the account with the given
+// identifier belongs to.
+func (c *SingBoxConfig) UserInboundTags(identifier string) []string {
+	inbounds, ok := c.Data["inbounds"].([]any)
+	if !ok {
+		return nil
+	}
+
+	var tags []string
+	for _, item := range inbounds {
+		inboundMap, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		tag, ok := inboundMap["tag"].(string)
+		if !ok {
+			continue
+		}
+
+		users, _ := inboundMap["users"].([]any)
+		for _, userItem := range users {
+			userMap, ok := userItem.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			name, _ := userMap["name"].(string)
+			username, _ := userMap["username"].(string)
+			if name == identifier || username == identifier {
+				tags = append(tags, tag)
+				break
+			}
+		}
+	}
+
+	return tags
+}
+
 func (c *SingBoxConfig) RegisterInbounds(storage storage.BaseStorage) error {
 	inbounds := c.ListInbounds()
 	for _, inbound := range inbounds {
@@ -1121,6 +1121,99 @@ func TestToJSON(t *testing.T) {
 	})
 }
 
+func TestUserInboundTags(t *testing.T) {
+	config := `{
+		"inbounds": [
+			{
+				"type": "vmess",
+				"tag": "vmess-in",
+				"listen_port": 1080,
+				"users": [
+					{
+						"name": "1.alice",
+						"uuid": "alice-uuid"
+					},
+					{
+						"name": "2.bob",
+						"uuid": "bob-uuid"
+					}
+				]
+			},
+			{
+				"type": "trojan",
+				"tag": "trojan-in",
+				"listen_port": 443,
+				"users": [
+					{
+						"name": "1.alice",
+						"password": "alice-password"
+					}
+				]
+			},
+			{
+				"type": "hysteria2",
+				"tag": "hy2-in",
+				"listen_port": 8443,
+				"users": [
+					{
+						"name": "3.carol",
+						"password": "carol-password"
+					}
+				]
+			},
+			{
+				"type": "shadowtls",
+				"tag": "shadowtls-in",
+				"listen_port": 8444
+			}
+		]
+	}`
+
+	singboxConfig, err := NewSingBoxConfig(config, "127.0.0.1", 8080)
+	if err != nil {
+		t.Fatalf("Failed to create SingBoxConfig: %v", err)
+	}
+
+	t.Run("user in several inbounds", func(t *testing.T) {
+		tags := singboxConfig.UserInboundTags("1.alice")
+		if len(tags) != 2 || tags[0] != "vmess-in" || tags[1] != "trojan-in" {
+			t.Errorf("Expected [vmess-in trojan-in], got %v", tags)
+		}
+	})
+
+	t.Run("user in single inbound", func(t *testing.T) {
+		tags := singboxConfig.UserInboundTags("2.bob")
+		if len(tags) != 1 || tags[0] != "vmess-in" {
+			t.Errorf("Expected [vmess-in], got %v", tags)
+		}
+	})
+
+	t.Run("unknown user", func(t *testing.T) {
+		if tags := singboxConfig.UserInboundTags("4.dave"); len(tags) != 0 {
+			t.Errorf("Expected no tags, got %v", tags)
+		}
+	})
+
+	t.Run("appended user", func(t *testing.T) {
+		user := models.User{ID: 5, Username: "erin", Key: "erin-key"}
+		if err := singboxConfig.AppendUser(user, models.Inbound{Tag: "hy2-in", Protocol: "hysteria2"}); err != nil {
+			t.Fatalf("AppendUser failed: %v", err)
+		}
+
+		tags := singboxConfig.UserInboundTags("5.erin")
+		if len(tags) != 1 || tags[0] != "hy2-in" {
+			t.Errorf("Expected [hy2-in], got %v", tags)
+		}
+	})
+
+	t.Run("no inbounds", func(t *testing.T) {
+		emptyConfig := &SingBoxConfig{Data: map[string]any{}}
+		if tags := emptyConfig.UserInboundTags("1.alice"); tags != nil {
+			t.Errorf("Expected nil, got %v", tags)
+		}
+	})
+}
+
 type MockStorage struct {
 	inbounds    map[string]*models.Inbound
 	shouldError bool
@@ -1327,3 +1420,80 @@ func TestPopUser_InvalidDataStructures(t *testing.T) {
 		}
 	})
 }
+
+func TestSetInbound(t *testing.T) {
+	config, err := NewSingBoxConfig(`{"inbounds": [{"type": "vless", "tag": "vless-in", "listen_port": 443}]}`, "127.0.0.1", 8080)
+	if err != nil {
+		t.Fatalf("Failed to create SingBoxConfig: %v", err)
+	}
+
+	replaced, err := config.SetInbound(map[string]any{"type": "trojan", "tag": "trojan-in", "listen_port": float64(8443)})
+	if err != nil || replaced {
+		t.Fatalf("expected inbound to be added, got replaced=%v err=%v", replaced, err)
+	}
+	if _, ok := config.InboundsByTag["trojan-in"]; !ok {
+		t.Error("expected trojan-in to be resolved")
+	}
+
+	replaced, err = config.SetInbound(map[string]any{"type": "vmess", "tag": "vless-in", "listen_port": float64(443)})
+	if err != nil || !replaced {
+		t.Fatalf("expected inbound to be replaced, got replaced=%v err=%v", replaced, err)
+	}
+	if got := config.InboundsByTag["vless-in"]["protocol"]; got != "vmess" {
+		t.Errorf("expected replaced inbound protocol vmess, got %v", got)
+	}
+	if len(config.Data["inbounds"].([]any)) != 2 || len(config.Inbounds) != 2 {
+		t.Errorf("expected 2 inbounds, got %d raw and %d resolved", len(config.Data["inbounds"].([]any)), len(config.Inbounds))
+	}
+
+	if _, err := config.SetInbound(map[string]any{"type": "vless"}); err == nil {
+		t.Error("expected error for inbound without tag")
+	}
+}
+
+func TestRemoveInbound(t *testing.T) {
+	config, err := NewSingBoxConfig(`{"inbounds": [{"type": "vless", "tag": "vless-in"}, {"type": "trojan", "tag": "trojan-in"}]}`, "127.0.0.1", 8080)
+	if err != nil {
+		t.Fatalf("Failed to create SingBoxConfig: %v", err)
+	}
+
+	if config.RemoveInbound("missing") {
+		t.Error("expected missing inbound not to be removed")
+	}
+	if !config.RemoveInbound("vless-in") {
+		t.Fatal("expected vless-in to be removed")
+	}
+	if config.HasInbound("vless-in") {
+		t.Error("expected vless-in to be gone")
+	}
+	if _, ok := config.InboundsByTag["vless-in"]; ok {
+		t.Error("expected vless-in to be gone from resolved inbounds")
+	}
+	if !config.HasInbound("trojan-in") || len(config.Inbounds) != 1 {
+		t.Error("expected trojan-in to remain")
+	}
+}
+
+func TestEnableClashAPI(t *testing.T) {
+	config, err := NewSingBoxConfig(`{"experimental": {"clash_api": {"external_controller": "0.0.0.0:9090", "default_mode": "rule"}}}`, "127.0.0.1", 8080)
+	if err != nil {
+		t.Fatalf("Failed to create SingBoxConfig: %v", err)
+	}
+
+	config.EnableClashAPI(9091, "secret")
+
+	experimental := config.Data["experimental"].(map[string]any)
+	clashAPI := experimental["clash_api"].(map[string]any)
+	if clashAPI["external_controller"] != "127.0.0.1:9091" {
+		t.Errorf("expected loopback controller, got %v", clashAPI["external_controller"])
+	}
+	if clashAPI["secret"] != "secret" {
+		t.Errorf("expected secret to be set, got %v", clashAPI["secret"])
+	}
+	if clashAPI["default_mode"] != "rule" {
+		t.Error("expected other clash_api settings to be kept")
+	}
+	if _, ok := experimental["v2ray_api"]; !ok {
+		t.Error("expected v2ray_api to be kept")
+	}
+}
@@ -0,0 +1,122 @@
+package singbox
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/highlight-apps/node-backend/backend/common"
+)
+
+// DiffConfigs compares two sing-box configs. Inbound users are ignored, since
+// the node manages them itself. Inbound changes need a restart, because the
+// node registers inbounds and attaches users on start; any other change is
+// picked up by a reload.
+func DiffConfigs(current, proposed *SingBoxConfig) *common.ConfigDiff {
+	diff := &common.ConfigDiff{}
+
+	currentInbounds := rawInbounds(current.Data)
+	proposedInbounds := rawInbounds(proposed.Data)
+
+	for _, tag := range sortedKeys(proposedInbounds) {
+		newInbound := proposedInbounds[tag]
+		oldInbound, exists := currentInbounds[tag]
+		if !exists {
+			diff.Added = append(diff.Added, common.InboundDiff{
+				Tag:      tag,
+				Protocol: inboundProtocol(newInbound),
+				NewPort:  inboundPort(newInbound),
+			})
+			continue
+		}
+
+		fields := changedKeys(oldInbound, newInbound, "users")
+		if len(fields) == 0 {
+			continue
+		}
+		diff.Modified = append(diff.Modified, common.InboundDiff{
+			Tag:           tag,
+			Protocol:      inboundProtocol(newInbound),
+			OldPort:       inboundPort(oldInbound),
+			NewPort:       inboundPort(newInbound),
+			PortChanged:   slices.Contains(fields, "listen_port"),
+			TLSChanged:    slices.Contains(fields, "tls"),
+			ChangedFields: fields,
+		})
+	}
+
+	for _, tag := range sortedKeys(currentInbounds) {
+		if _, exists := proposedInbounds[tag]; exists {
+			continue
+		}
+		oldInbound := currentInbounds[tag]
+		diff.Removed = append(diff.Removed, common.InboundDiff{
+			Tag:      tag,
+			Protocol: inboundProtocol(oldInbound),
+			OldPort:  inboundPort(oldInbound),
+		})
+	}
+
+	diff.ChangedSections = changedKeys(current.Data, proposed.Data, "inbounds")
+
+	diff.RestartRequired = len(diff.Added) > 0 || len(diff.Removed) > 0 || len(diff.Modified) > 0
+	diff.ReloadRequired = !diff.RestartRequired && len(diff.ChangedSections) > 0
+
+	return diff
+}
+
+// rawInbounds returns the inbounds of a config as written, by tag.
+func rawInbounds(data map[string]any) map[string]map[string]any {
+	inbounds := make(map[string]map[string]any)
+	items, _ := data["inbounds"].([]any)
+	for _, item := range items {
+		inbound, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		if tag, ok := inbound["tag"].(string); ok {
+			inbounds[tag] = inbound
+		}
+	}
+	return inbounds
+}
+
+// changedKeys returns the sorted keys whose values differ between a and b.
+func changedKeys(a, b map[string]any, ignore ...string) []string {
+	keys := make(map[string]struct{}, len(a)+len(b))
+	for key := range a {
+		keys[key] = struct{}{}
+	}
+	for key := range b {
+		keys[key] = struct{}{}
+	}
+
+	var changed []string
+	for _, key := range sortedKeys(keys) {
+		if slices.Contains(ignore, key) {
+			continue
+		}
+		if !reflect.DeepEqual(a[key], b[key]) {
+			changed = append(changed, key)
+		}
+	}
+	return changed
+}
+
+func inboundProtocol(inbound map[string]any) string {
+	protocol, _ := inbound["type"].(string)
+	return protocol
+}
+
+func inboundPort(inbound map[string]any) int {
+	port, _ := inbound["listen_port"].(float64)
+	return int(port)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
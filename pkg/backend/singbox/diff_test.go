@@ -0,0 +1,99 @@
+package singbox
+
+import (
+	"slices"
+	"testing"
+)
+
+const diffBaseConfig = `{
+	"log": {"level": "info"},
+	"inbounds": [
+		{"type": "vless", "tag": "vless-in", "listen_port": 443, "tls": {"enabled": true, "server_name": "a.example.com"}, "users": []},
+		{"type": "shadowsocks", "tag": "ss-in", "listen_port": 8388, "method": "2022-blake3-aes-128-gcm"},
+		{"type": "trojan", "tag": "trojan-in", "listen_port": 8443}
+	],
+	"outbounds": [{"type": "direct", "tag": "direct"}]
+}`
+
+func mustDiffConfig(t *testing.T, config string) *SingBoxConfig {
+	t.Helper()
+	c, err := NewSingBoxConfig(config, "127.0.0.1", 0)
+	if err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	return c
+}
+
+func TestDiffConfigs(t *testing.T) {
+	t.Run("identical configs", func(t *testing.T) {
+		diff := DiffConfigs(mustDiffConfig(t, diffBaseConfig), mustDiffConfig(t, diffBaseConfig))
+		if len(diff.Added)+len(diff.Removed)+len(diff.Modified)+len(diff.ChangedSections) != 0 {
+			t.Errorf("expected no changes, got %+v", diff)
+		}
+		if diff.RestartRequired || diff.ReloadRequired {
+			t.Errorf("expected neither restart nor reload, got %+v", diff)
+		}
+	})
+
+	t.Run("users are ignored", func(t *testing.T) {
+		proposed := mustDiffConfig(t, diffBaseConfig)
+		inbounds := proposed.Data["inbounds"].([]any)
+		inbounds[0].(map[string]any)["users"] = []any{map[string]any{"name": "1.user"}}
+
+		diff := DiffConfigs(mustDiffConfig(t, diffBaseConfig), proposed)
+		if len(diff.Modified) != 0 {
+			t.Errorf("expected no modified inbounds, got %+v", diff.Modified)
+		}
+	})
+
+	t.Run("inbound changes", func(t *testing.T) {
+		proposed := `{
+			"log": {"level": "info"},
+			"inbounds": [
+				{"type": "vless", "tag": "vless-in", "listen_port": 8443, "tls": {"enabled": true, "server_name": "b.example.com"}},
+				{"type": "shadowsocks", "tag": "ss-in", "listen_port": 8388, "method": "2022-blake3-aes-128-gcm"},
+				{"type": "vmess", "tag": "vmess-in", "listen_port": 10000}
+			],
+			"outbounds": [{"type": "direct", "tag": "direct"}]
+		}`
+
+		diff := DiffConfigs(mustDiffConfig(t, diffBaseConfig), mustDiffConfig(t, proposed))
+
+		if len(diff.Added) != 1 || diff.Added[0].Tag != "vmess-in" || diff.Added[0].NewPort != 10000 || diff.Added[0].Protocol != "vmess" {
+			t.Errorf("unexpected added inbounds: %+v", diff.Added)
+		}
+		if len(diff.Removed) != 1 || diff.Removed[0].Tag != "trojan-in" || diff.Removed[0].OldPort != 8443 {
+			t.Errorf("unexpected removed inbounds: %+v", diff.Removed)
+		}
+		if len(diff.Modified) != 1 {
+			t.Fatalf("expected one modified inbound, got %+v", diff.Modified)
+		}
+		modified := diff.Modified[0]
+		if modified.Tag != "vless-in" || !modified.PortChanged || !modified.TLSChanged {
+			t.Errorf("unexpected modified inbound: %+v", modified)
+		}
+		if modified.OldPort != 443 || modified.NewPort != 8443 {
+			t.Errorf("expected port 443 -> 8443, got %d -> %d", modified.OldPort, modified.NewPort)
+		}
+		if !slices.Equal(modified.ChangedFields, []string{"listen_port", "tls"}) {
+			t.Errorf("unexpected changed fields: %v", modified.ChangedFields)
+		}
+		if !diff.RestartRequired || diff.ReloadRequired {
+			t.Errorf("expected restart only, got restart=%v reload=%v", diff.RestartRequired, diff.ReloadRequired)
+		}
+	})
+
+	t.Run("other sections only", func(t *testing.T) {
+		proposed := mustDiffConfig(t, diffBaseConfig)
+		proposed.Data["log"] = map[string]any{"level": "debug"}
+		proposed.Data["route"] = map[string]any{"final": "direct"}
+
+		diff := DiffConfigs(mustDiffConfig(t, diffBaseConfig), proposed)
+		if !slices.Equal(diff.ChangedSections, []string{"log", "route"}) {
+			t.Errorf("unexpected changed sections: %v", diff.ChangedSections)
+		}
+		if diff.RestartRequired || !diff.ReloadRequired {
+			t.Errorf("expected reload only, got restart=%v reload=%v", diff.RestartRequired, diff.ReloadRequired)
+		}
+	})
+}
@@ -0,0 +1,309 @@
+package singbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/highlight-apps/node-backend/backend/common"
+	"github.com/highlight-apps/node-backend/backend/common/models"
+)
+
+// AddInbound adds an inbound to the running config and reloads sing-box.
+// Users the storage already has on the tag are attached to it.
+func (s *SingBoxBackend) AddInbound(ctx context.Context, inboundConfig any) (models.Inbound, error) {
+	return s.putInbound(ctx, inboundConfig, false)
+}
+
+// ReplaceInbound replaces the inbound with the same tag and reloads sing-box.
+// The users of the old inbound are attached to the new one.
+func (s *SingBoxBackend) ReplaceInbound(ctx context.Context, inboundConfig any) (models.Inbound, error) {
+	return s.putInbound(ctx, inboundConfig, true)
+}
+
+// RemoveInbound removes an inbound, detaching its users, and reloads sing-box.
+func (s *SingBoxBackend) RemoveInbound(ctx context.Context, tag string) error {
+	s.restartMutex.Lock()
+	defer s.restartMutex.Unlock()
+	s.configModificationMutex.Lock()
+	defer s.configModificationMutex.Unlock()
+
+	if s.config == nil {
+		return common.ErrConfigNotSet
+	}
+	if !s.config.HasInbound(tag) {
+		return fmt.Errorf("%w: %s", common.ErrInboundNotFound, tag)
+	}
+
+	configFile, updatedConfigFile, err := s.updatedConfigFile(func(config *SingBoxConfig) { config.RemoveInbound(tag) })
+	if err != nil {
+		return err
+	}
+
+	// Collect first, so traffic is attributed to the inbounds it went through.
+	s.collectTraffic(ctx)
+
+	rollback := &inboundRollback{tag: tag, previous: s.config.inboundConfig(tag), configFile: configFile}
+	inbound, registered := s.findInbound(tag)
+	s.config.RemoveInbound(tag)
+	s.refreshInboundTags()
+
+	if err := s.applyInboundChange(ctx, rollback, updatedConfigFile); err != nil {
+		return err
+	}
+
+	if registered {
+		if err := s.storage.RemoveInbound(inbound); err != nil {
+			return s.rollbackInbound(ctx, rollback, fmt.Errorf("failed to remove inbound %s from storage: %w", tag, err))
+		}
+	}
+	return nil
+}
+
+func (s *SingBoxBackend) putInbound(ctx context.Context, inboundConfig any, replace bool) (models.Inbound, error) {
+	raw, err := parseInbound(inboundConfig)
+	if err != nil {
+		return models.Inbound{}, err
+	}
+	inbound, err := resolveInbound(raw)
+	if err != nil {
+		return models.Inbound{}, err
+	}
+
+	s.restartMutex.Lock()
+	defer s.restartMutex.Unlock()
+	s.configModificationMutex.Lock()
+	defer s.configModificationMutex.Unlock()
+
+	if s.config == nil {
+		return models.Inbound{}, common.ErrConfigNotSet
+	}
+	exists := s.config.HasInbound(inbound.Tag)
+	if replace && !exists {
+		return models.Inbound{}, fmt.Errorf("%w: %s", common.ErrInboundNotFound, inbound.Tag)
+	}
+	if !replace && exists {
+		return models.Inbound{}, fmt.Errorf("%w: %s", common.ErrInboundAlreadyExists, inbound.Tag)
+	}
+
+	// The config for disk is encoded before users are attached to the
+	// inbound, since it shares the map with the running config.
+	configFile, updatedConfigFile, err := s.updatedConfigFile(func(config *SingBoxConfig) { config.SetInbound(raw) })
+	if err != nil {
+		return models.Inbound{}, err
+	}
+
+	users, err := s.storage.ListInboundUsers(inbound.Tag)
+	if err != nil {
+		return models.Inbound{}, fmt.Errorf("failed to list users for inbound %s: %w", inbound.Tag, err)
+	}
+
+	// Collect first, so traffic is attributed to the inbounds it went through.
+	s.collectTraffic(ctx)
+
+	rollback := &inboundRollback{tag: inbound.Tag, previous: s.config.inboundConfig(inbound.Tag), configFile: configFile}
+	previousInbound, registered := s.findInbound(inbound.Tag)
+
+	// Users are attached again below, with accounts for the new protocol.
+	if _, err := s.config.SetInbound(raw); err != nil {
+		return models.Inbound{}, &common.ConfigError{Stage: common.ConfigStageParse, Messages: []string{err.Error()}}
+	}
+	s.refreshInboundTags()
+
+	for _, user := range users {
+		if err := s.config.AppendUser(user, inbound); err != nil {
+			return models.Inbound{}, s.rollbackInbound(ctx, rollback,
+				fmt.Errorf("failed to append user %s to inbound %s: %w", user.Username, inbound.Tag, err))
+		}
+	}
+
+	if err := s.applyInboundChange(ctx, rollback, updatedConfigFile); err != nil {
+		return models.Inbound{}, err
+	}
+
+	if err := s.registerInbound(inbound, users); err != nil {
+		// Put the storage back in line with the restored config.
+		var restoreErr error
+		if registered {
+			restoreErr = s.storage.RegisterInbound(previousInbound)
+		} else {
+			restoreErr = s.storage.RemoveInbound(inbound)
+		}
+		for _, user := range users {
+			restoreErr = errors.Join(restoreErr, s.storage.UpdateUserInbounds(user, user.Inbounds))
+		}
+		if restoreErr != nil {
+			s.log(ctx).Error("failed to restore storage of inbound:", inbound.Tag, restoreErr)
+		}
+		return models.Inbound{}, s.rollbackInbound(ctx, rollback, err)
+	}
+
+	return inbound, nil
+}
+
+// inboundRollback is the state an inbound change restores when one of its
+// steps fails.
+type inboundRollback struct {
+	tag string
+	// previous is the config of the inbound before the change, or nil if
+	// the change adds it.
+	previous   map[string]any
+	configFile string
+	reloaded   bool
+	saved      bool
+}
+
+// applyInboundChange reloads the core with the changed config and then saves
+// it to disk. The change is rolled back if either fails.
+func (s *SingBoxBackend) applyInboundChange(ctx context.Context, rollback *inboundRollback, configFile string) error {
+	// A failed reload may have left the core with either config, so it is
+	// reloaded on rollback in any case.
+	rollback.reloaded = true
+	if err := s.reloadIfRunning(ctx); err != nil {
+		return s.rollbackInbound(ctx, rollback, err)
+	}
+
+	if err := s.saveConfig(configFile, false); err != nil {
+		return s.rollbackInbound(ctx, rollback, fmt.Errorf("failed to save config: %w", err))
+	}
+	rollback.saved = true
+	return nil
+}
+
+// rollbackInbound restores the inbound in the running config, and in the core
+// and on disk once they were updated, then returns err. Failures to restore
+// are only logged, since err is what the caller needs to see.
+func (s *SingBoxBackend) rollbackInbound(ctx context.Context, rollback *inboundRollback, err error) error {
+	if rollback.previous != nil {
+		_, _ = s.config.SetInbound(rollback.previous)
+	} else {
+		s.config.RemoveInbound(rollback.tag)
+	}
+	s.refreshInboundTags()
+
+	if rollback.reloaded {
+		if reloadErr := s.reloadIfRunning(ctx); reloadErr != nil {
+			s.log(ctx).Error("failed to reload restored config:", reloadErr)
+		}
+	}
+	if rollback.saved {
+		if saveErr := s.saveConfig(rollback.configFile, false); saveErr != nil {
+			s.log(ctx).Error("failed to restore config file:", saveErr)
+		}
+	}
+	return err
+}
+
+// reloadIfRunning applies the config to a running core. A stopped one picks
+// the change up from the config on disk when it starts.
+func (s *SingBoxBackend) reloadIfRunning(ctx context.Context) error {
+	if !s.runner.IsRunning() {
+		return nil
+	}
+	return s.reload(ctx)
+}
+
+// registerInbound registers the inbound in storage and points the storage
+// records of its users at it.
+func (s *SingBoxBackend) registerInbound(inbound models.Inbound, users []models.User) error {
+	if err := s.storage.RegisterInbound(inbound); err != nil {
+		return fmt.Errorf("failed to register inbound %s: %w", inbound.Tag, err)
+	}
+
+	for _, user := range users {
+		inbounds := make([]models.Inbound, 0, len(user.Inbounds))
+		for _, userInbound := range user.Inbounds {
+			if userInbound.Tag == inbound.Tag {
+				userInbound = inbound
+			}
+			inbounds = append(inbounds, userInbound)
+		}
+		if err := s.storage.UpdateUserInbounds(user, inbounds); err != nil {
+			return fmt.Errorf("failed to update inbounds of user %s: %w", user.Username, err)
+		}
+	}
+	return nil
+}
+
+func (s *SingBoxBackend) findInbound(tag string) (models.Inbound, bool) {
+	for _, inbound := range s.inbounds {
+		if inbound.Tag == tag {
+			return inbound, true
+		}
+	}
+	return models.Inbound{}, false
+}
+
+func (s *SingBoxBackend) refreshInboundTags() {
+	inbounds := s.config.ListInbounds()
+	inboundTags := make(map[string]bool, len(inbounds))
+	for _, inbound := range inbounds {
+		inboundTags[inbound.Tag] = true
+	}
+	s.inbounds = inbounds
+	s.inboundTags = inboundTags
+}
+
+// updatedConfigFile applies a change to the config on disk, so it survives a
+// restart of the backend. It returns the current and the changed file, and
+// leaves saving to the caller.
+func (s *SingBoxBackend) updatedConfigFile(update func(config *SingBoxConfig)) (string, string, error) {
+	data, err := os.ReadFile(s.configPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var configData map[string]any
+	if err := json.Unmarshal(data, &configData); err != nil {
+		return "", "", fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	update(&SingBoxConfig{Data: configData})
+
+	prettyData, err := json.MarshalIndent(configData, "", "  ")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to format config: %w", err)
+	}
+	return string(data), string(prettyData), nil
+}
+
+// parseInbound decodes a single inbound given as JSON or as a map.
+func parseInbound(inboundConfig any) (map[string]any, error) {
+	var data []byte
+	switch cfg := inboundConfig.(type) {
+	case map[string]any:
+		return cfg, nil
+	case string:
+		data = []byte(cfg)
+	case []byte:
+		data = cfg
+	default:
+		return nil, fmt.Errorf("%w: %T", common.ErrUnknownConfigType, inboundConfig)
+	}
+
+	var inbound map[string]any
+	if err := json.Unmarshal(data, &inbound); err != nil {
+		return nil, &common.ConfigError{Stage: common.ConfigStageParse, Messages: []string{err.Error()}}
+	}
+	return inbound, nil
+}
+
+// resolveInbound builds the inbound the node registers for a config entry.
+func resolveInbound(raw map[string]any) (models.Inbound, error) {
+	config := &SingBoxConfig{
+		Data:          map[string]any{"inbounds": []any{raw}},
+		InboundsByTag: make(map[string]map[string]any),
+	}
+	config.resolveInbounds()
+
+	inbounds := config.ListInbounds()
+	if len(inbounds) != 1 {
+		return models.Inbound{}, &common.ConfigError{
+			Stage:    common.ConfigStageParse,
+			Messages: []string{"inbound needs a tag and a supported type"},
+		}
+	}
+	return inbounds[0], nil
+}
@@ -0,0 +1,273 @@
+package singbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/highlight-apps/node-backend/backend/common"
+	"github.com/highlight-apps/node-backend/backend/common/models"
+	"github.com/highlight-apps/node-backend/logging"
+)
+
+const inboundsTestConfig = `{
+	"inbounds": [
+		{"type": "vless", "tag": "vless-in", "listen_port": 443}
+	]
+}`
+
+type inboundsTestStorage struct {
+	MockStorage
+	users     map[int64]models.User
+	removeErr error
+}
+
+func (s *inboundsTestStorage) ListInboundUsers(tag string) ([]models.User, error) {
+	var users []models.User
+	for _, user := range s.users {
+		for _, inbound := range user.Inbounds {
+			if inbound.Tag == tag {
+				users = append(users, user)
+				break
+			}
+		}
+	}
+	return users, nil
+}
+
+func (s *inboundsTestStorage) UpdateUserInbounds(user models.User, inbounds []models.Inbound) error {
+	user.Inbounds = inbounds
+	s.users[user.ID] = user
+	return nil
+}
+
+func (s *inboundsTestStorage) RemoveInbound(inbound models.Inbound) error {
+	if s.removeErr != nil {
+		return s.removeErr
+	}
+	delete(s.inbounds, inbound.Tag)
+	return nil
+}
+
+func newInboundsTestBackend(t *testing.T, store *inboundsTestStorage) *SingBoxBackend {
+	t.Helper()
+
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(inboundsTestConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := logging.NewStdLogger()
+	runner, err := NewSingboxRunner("sing-box", "", logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := NewSingBoxConfig(inboundsTestConfig, "127.0.0.1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend := &SingBoxBackend{
+		config:         config,
+		runner:         runner,
+		storage:        store,
+		configPath:     configPath,
+		fullConfigPath: configPath + ".full",
+		pendingTraffic: make(map[int64]*common.UserTraffic),
+		logger:         logger,
+	}
+	backend.refreshInboundTags()
+	return backend
+}
+
+func readInboundTags(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var config map[string]any
+	if err := json.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	var tags []string
+	for _, inbound := range config["inbounds"].([]any) {
+		tags = append(tags, inbound.(map[string]any)["tag"].(string))
+	}
+	return tags
+}
+
+func TestSingBoxBackend_AddInbound(t *testing.T) {
+	store := &inboundsTestStorage{
+		MockStorage: MockStorage{inbounds: make(map[string]*models.Inbound)},
+		users: map[int64]models.User{
+			1: {ID: 1, Username: "user", Key: "key", Inbounds: []models.Inbound{{Tag: "ss-in"}}},
+		},
+	}
+	backend := newInboundsTestBackend(t, store)
+
+	inbound, err := backend.AddInbound(context.Background(), `{"type": "shadowsocks", "tag": "ss-in", "listen_port": 8388, "method": "chacha20-ietf-poly1305"}`)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if inbound.Tag != "ss-in" || inbound.Protocol != "shadowsocks" {
+		t.Errorf("unexpected inbound: %+v", inbound)
+	}
+	if !backend.ContainsTag("ss-in") {
+		t.Error("expected backend to contain the new tag")
+	}
+	if _, ok := store.inbounds["ss-in"]; !ok {
+		t.Error("expected inbound to be registered in storage")
+	}
+	if tags := backend.config.UserInboundTags("1.user"); len(tags) != 1 || tags[0] != "ss-in" {
+		t.Errorf("expected storage user to be attached, got %v", tags)
+	}
+	if got := store.users[1].Inbounds[0].Protocol; got != "shadowsocks" {
+		t.Errorf("expected user inbound to be updated, got protocol %q", got)
+	}
+	if tags := readInboundTags(t, backend.configPath); len(tags) != 2 {
+		t.Errorf("expected config on disk to have 2 inbounds, got %v", tags)
+	}
+
+	_, err = backend.AddInbound(context.Background(), `{"type": "vless", "tag": "vless-in", "listen_port": 8443}`)
+	if !errors.Is(err, common.ErrInboundAlreadyExists) {
+		t.Errorf("expected ErrInboundAlreadyExists, got %v", err)
+	}
+
+	_, err = backend.AddInbound(context.Background(), `{"type": "unknown", "tag": "x"}`)
+	if !errors.Is(err, common.ErrInvalidConfig) {
+		t.Errorf("expected ErrInvalidConfig, got %v", err)
+	}
+}
+
+func TestSingBoxBackend_ReplaceInbound(t *testing.T) {
+	store := &inboundsTestStorage{
+		MockStorage: MockStorage{inbounds: make(map[string]*models.Inbound)},
+		users: map[int64]models.User{
+			1: {ID: 1, Username: "user", Key: "key", Inbounds: []models.Inbound{{Tag: "vless-in", Protocol: "vless"}}},
+		},
+	}
+	backend := newInboundsTestBackend(t, store)
+
+	_, err := backend.ReplaceInbound(context.Background(), `{"type": "vless", "tag": "missing", "listen_port": 443}`)
+	if !errors.Is(err, common.ErrInboundNotFound) {
+		t.Errorf("expected ErrInboundNotFound, got %v", err)
+	}
+
+	inbound, err := backend.ReplaceInbound(context.Background(), `{"type": "trojan", "tag": "vless-in", "listen_port": 8443}`)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if inbound.Protocol != "trojan" {
+		t.Errorf("expected protocol trojan, got %q", inbound.Protocol)
+	}
+	if tags := backend.config.UserInboundTags("1.user"); len(tags) != 1 || tags[0] != "vless-in" {
+		t.Errorf("expected user to be attached again, got %v", tags)
+	}
+	if got := store.users[1].Inbounds[0].Protocol; got != "trojan" {
+		t.Errorf("expected user inbound to be updated, got protocol %q", got)
+	}
+}
+
+func TestSingBoxBackend_RemoveInbound(t *testing.T) {
+	store := &inboundsTestStorage{
+		MockStorage: MockStorage{inbounds: map[string]*models.Inbound{"vless-in": {Tag: "vless-in"}}},
+		users:       map[int64]models.User{},
+	}
+	backend := newInboundsTestBackend(t, store)
+
+	if err := backend.RemoveInbound(context.Background(), "missing"); !errors.Is(err, common.ErrInboundNotFound) {
+		t.Errorf("expected ErrInboundNotFound, got %v", err)
+	}
+
+	if err := backend.RemoveInbound(context.Background(), "vless-in"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if backend.ContainsTag("vless-in") {
+		t.Error("expected tag to be removed from backend")
+	}
+	if _, ok := store.inbounds["vless-in"]; ok {
+		t.Error("expected inbound to be removed from storage")
+	}
+	if tags := readInboundTags(t, backend.configPath); len(tags) != 0 {
+		t.Errorf("expected config on disk to have no inbounds, got %v", tags)
+	}
+}
+
+func TestSingBoxBackend_InboundChangeRollback(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(backend *SingBoxBackend) error
+	}{
+		{
+			name: "add",
+			change: func(backend *SingBoxBackend) error {
+				_, err := backend.AddInbound(context.Background(), `{"type": "trojan", "tag": "trojan-in", "listen_port": 8443}`)
+				return err
+			},
+		},
+		{
+			name: "replace",
+			change: func(backend *SingBoxBackend) error {
+				_, err := backend.ReplaceInbound(context.Background(), `{"type": "trojan", "tag": "vless-in", "listen_port": 8443}`)
+				return err
+			},
+		},
+		{
+			name: "remove",
+			change: func(backend *SingBoxBackend) error {
+				return backend.RemoveInbound(context.Background(), "vless-in")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &inboundsTestStorage{
+				MockStorage: MockStorage{
+					inbounds:    map[string]*models.Inbound{"vless-in": {Tag: "vless-in", Protocol: "vless"}},
+					shouldError: true,
+				},
+				users:     map[int64]models.User{},
+				removeErr: errors.New("storage error"),
+			}
+			backend := newInboundsTestBackend(t, store)
+
+			if err := tt.change(backend); err == nil {
+				t.Fatal("expected the storage error")
+			}
+
+			if tags := backend.config.ListInbounds(); len(tags) != 1 || tags[0].Tag != "vless-in" || tags[0].Protocol != "vless" {
+				t.Errorf("expected the running config to be restored, got %+v", tags)
+			}
+			if !backend.ContainsTag("vless-in") || backend.ContainsTag("trojan-in") {
+				t.Errorf("expected the backend tags to be restored, got %v", backend.inboundTags)
+			}
+			data, err := os.ReadFile(backend.configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != inboundsTestConfig {
+				t.Errorf("expected config on disk to be restored, got %s", data)
+			}
+			if len(store.inbounds) != 1 || store.inbounds["vless-in"].Protocol != "vless" {
+				t.Errorf("expected storage to keep the old inbound, got %v", store.inbounds)
+			}
+		})
+	}
+}
+
+func TestSingBoxBackend_InboundsWithoutConfig(t *testing.T) {
+	backend := &SingBoxBackend{}
+
+	if _, err := backend.AddInbound(context.Background(), `{"type": "vless", "tag": "vless-in"}`); !errors.Is(err, common.ErrConfigNotSet) {
+		t.Errorf("expected ErrConfigNotSet, got %v", err)
+	}
+	if err := backend.RemoveInbound(context.Background(), "vless-in"); !errors.Is(err, common.ErrConfigNotSet) {
+		t.Errorf("expected ErrConfigNotSet, got %v", err)
+	}
+}
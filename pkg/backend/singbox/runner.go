@@ -1,9 +1,11 @@
 package singbox
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"sync"
 	"syscall"
 
 	"github.com/highlight-apps/node-backend/backend/common"
@@ -16,13 +18,19 @@ var fileCloser func(*os.File) error = (*os.File).Close
 
 type SingboxRunner struct {
 	*common.BaseRunner
-	assetsPath     string
+	assetsPath string
+
+	// configMu guards configFilePath, the file the running core was started
+	// with and rereads on reload.
+	configMu       sync.Mutex
 	configFilePath string
 }
 
 var _ common.Runner = (*SingboxRunner)(nil)
 
-func NewSingboxRunner(executablePath string, logger logging.Logger) (*SingboxRunner, error) {
+// NewSingboxRunner creates a runner for the sing-box binary. Relative paths
+// in its configs, such as rule sets, resolve against assetsPath if it is set.
+func NewSingboxRunner(executablePath string, assetsPath string, logger logging.Logger) (*SingboxRunner, error) {
 	var l logging.Logger
 	if logger != nil {
 		l = logger
@@ -34,6 +42,7 @@ func NewSingboxRunner(executablePath string, logger logging.Logger) (*SingboxRun
 	Runner := common.NewBaseRunner(executablePath, l, pc)
 	r := &SingboxRunner{
 		BaseRunner: Runner,
+		assetsPath: assetsPath,
 	}
 	return r, nil
 }
@@ -58,7 +67,7 @@ func (r *SingboxRunner) Start(config string) error {
 		return fmt.Errorf("failed to create config file: %w", err)
 	}
 
-	cmd := execCommand(r.ExecutablePath, "run", "--disable-color", "-c", configPath)
+	cmd := execCommand(r.ExecutablePath, r.commandArgs("run", configPath)...)
 
 	r.SetupOnStopHandler()
 
@@ -81,10 +90,58 @@ func (r *SingboxRunner) Restart(config string) error {
 	})
 }
 
-func (r *SingboxRunner) Reload() error {
+// Reload rewrites the config file of the running core and signals it to read
+// the file again.
+func (r *SingboxRunner) Reload(config string) error {
+	r.configMu.Lock()
+	defer r.configMu.Unlock()
+
+	if r.configFilePath == "" || !r.Controller.IsRunning() {
+		return common.ErrProcessNotRunning
+	}
+	if err := os.WriteFile(r.configFilePath, []byte(config), 0600); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
 	return r.Controller.Reload(syscall.SIGHUP)
 }
 
+// Check runs sing-box check against the config without starting it.
+func (r *SingboxRunner) Check(config string) error {
+	configFile, err := osCreateTemp(os.TempDir(), "singbox-check-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary config file: %w", err)
+	}
+	defer os.Remove(configFile.Name())
+
+	if _, err := configFile.WriteString(config); err != nil {
+		configFile.Close()
+		return fmt.Errorf("failed to write config to file: %w", err)
+	}
+	if err := fileCloser(configFile); err != nil {
+		return fmt.Errorf("failed to close config file: %w", err)
+	}
+
+	output, err := execCommand(r.ExecutablePath, r.commandArgs("check", configFile.Name())...).CombinedOutput()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return common.NewCheckError(output)
+		}
+		return fmt.Errorf("failed to run sing-box check: %w", err)
+	}
+	return nil
+}
+
+// commandArgs returns the arguments of a sing-box command reading the config
+// at configPath.
+func (r *SingboxRunner) commandArgs(command, configPath string) []string {
+	args := []string{command, "--disable-color"}
+	if r.assetsPath != "" {
+		args = append(args, "-D", r.assetsPath)
+	}
+	return append(args, "-c", configPath)
+}
+
 func (r *SingboxRunner) Stop() error {
 	if !r.IsRunning() {
 		return nil
@@ -102,6 +159,9 @@ func (r *SingboxRunner) Stop() error {
 }
 
 func (r *SingboxRunner) createConfigFile(config string) (string, error) {
+	r.configMu.Lock()
+	defer r.configMu.Unlock()
+
 	if r.configFilePath != "" {
 		os.Remove(r.configFilePath)
 	}
@@ -130,6 +190,9 @@ func (r *SingboxRunner) createConfigFile(config string) (string, error) {
 }
 
 func (r *SingboxRunner) removeConfigFile() {
+	r.configMu.Lock()
+	defer r.configMu.Unlock()
+
 	if r.configFilePath != "" {
 		if err := os.Remove(r.configFilePath); err != nil {
 			r.Logger.Error("failed to remove config file:", err)
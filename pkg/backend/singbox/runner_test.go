@@ -24,6 +24,9 @@ const (
 	testConfig       = `{"test": "config"}`
 	mockSleepCommand = "echo 'sing-box started'; sleep 10"
 	mockExitCommand  = "echo 'sing-box started'; exit 0"
+
+	testSingboxExecutablePath = "testdata/sing-box"
+	testSingboxConfigPath     = "singbox_config.json"
 )
 
 func TestMain(m *testing.M) {
@@ -40,11 +43,11 @@ func TestMain(m *testing.M) {
 }
 func TestSingboxRunner_New_Success(t *testing.T) {
 	tl := logging.NewStdLogger()
-	exePath, err := exec.LookPath(common.DefaultSingboxExecutablePath)
+	exePath, err := exec.LookPath(testSingboxExecutablePath)
 	if err != nil {
 		t.Fatalf("failed to find singbox executable: %v", err)
 	}
-	r, err := NewSingboxRunner(exePath, tl)
+	r, err := NewSingboxRunner(exePath, "", tl)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -59,11 +62,11 @@ func TestSingboxRunner_New_Success(t *testing.T) {
 	}
 }
 func TestSingboxRunner_New_DefaultLogger(t *testing.T) {
-	exePath, err := exec.LookPath(common.DefaultSingboxExecutablePath)
+	exePath, err := exec.LookPath(testSingboxExecutablePath)
 	if err != nil {
 		t.Fatalf("failed to find singbox executable: %v", err)
 	}
-	r, err := NewSingboxRunner(exePath, nil)
+	r, err := NewSingboxRunner(exePath, "", nil)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -112,7 +115,7 @@ func TestSingboxRunner_Start_SetupCmdError(t *testing.T) {
 		setupCmdErr: fmt.Errorf("setup cmd error"),
 	}
 
-	runner := common.NewBaseRunner(common.DefaultSingboxExecutablePath, logging.NewStdLogger(), mockCtrl)
+	runner := common.NewBaseRunner(testSingboxExecutablePath, logging.NewStdLogger(), mockCtrl)
 	r := &SingboxRunner{
 		BaseRunner: runner,
 	}
@@ -150,7 +153,7 @@ func TestSingboxRunner_Restart_AlreadyRestarting(t *testing.T) {
 	mockCtrl := &mockProcessController{
 		isRunning: true,
 	}
-	Runner := common.NewBaseRunner(common.DefaultSingboxExecutablePath, logging.NewStdLogger(), mockCtrl)
+	Runner := common.NewBaseRunner(testSingboxExecutablePath, logging.NewStdLogger(), mockCtrl)
 	r := &SingboxRunner{
 		BaseRunner: Runner,
 	}
@@ -189,14 +192,23 @@ func TestSingboxRunner_Reload_Success(t *testing.T) {
 		t.Fatalf("expected no error on start, got %v", err)
 	}
 	defer r.Stop()
-	err = r.Reload()
+	configPath := r.configFilePath
+	reloaded := `{"inbounds": []}`
+	err = r.Reload(reloaded)
 	if err != nil {
 		t.Errorf("expected no error on reload, got %v", err)
 	}
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("failed to read config file: %v", err)
+	}
+	if string(content) != reloaded {
+		t.Errorf("expected the config file to be rewritten, got %s", content)
+	}
 }
 func TestSingboxRunner_Reload_NotRunning(t *testing.T) {
 	r := newTestRunner(t)
-	err := r.Reload()
+	err := r.Reload("{}")
 	if err == nil {
 		t.Fatal("expected error reloading not running process, got nil")
 	}
@@ -693,13 +705,16 @@ func (m *mockProcessController) SubscribeLogs(ctx context.Context) <-chan string
 	close(ch)
 	return ch
 }
+func (m *mockProcessController) ProcessInfo() common.ProcessInfo {
+	return common.ProcessInfo{}
+}
 func newTestRunner(t *testing.T) *SingboxRunner {
 	tl := logging.NewStdLogger()
-	exePath, err := exec.LookPath(common.DefaultSingboxExecutablePath)
+	exePath, err := exec.LookPath(testSingboxExecutablePath)
 	if err != nil {
 		t.Skipf("failed to find singbox executable: %v", err)
 	}
-	r, err := NewSingboxRunner(exePath, tl)
+	r, err := NewSingboxRunner(exePath, "", tl)
 	if err != nil {
 		t.Fatalf("failed to create runner: %v", err)
 	}
@@ -713,7 +728,7 @@ func setupMockExecCommand(_ *testing.T) func() {
 	return func() { execCommand = origExec }
 }
 func loadConfig() (string, error) {
-	path := common.DefaultSingboxConfigPath
+	path := testSingboxConfigPath
 	data, err := assets.ConfigFS.ReadFile(path)
 	if err != nil {
 		return "", fmt.Errorf("%w (%s): %w", common.ErrFailedReadEmbeddedConfig, path, err)
@@ -721,14 +736,104 @@ func loadConfig() (string, error) {
 	return string(data), nil
 }
 
+func TestSingboxRunner_Check(t *testing.T) {
+	origExec := execCommand
+	defer func() { execCommand = origExec }()
+
+	r, err := NewSingboxRunner("sing-box", "", logging.NewStdLogger())
+	if err != nil {
+		t.Fatalf("failed to create runner: %v", err)
+	}
+
+	t.Run("valid config", func(t *testing.T) {
+		var gotArgs []string
+		execCommand = func(name string, args ...string) *exec.Cmd {
+			gotArgs = args
+			return exec.Command("sh", "-c", "exit 0")
+		}
+		if err := r.Check(testConfig); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if len(gotArgs) == 0 || gotArgs[0] != "check" {
+			t.Errorf("expected check command, got %v", gotArgs)
+		}
+		configPath := gotArgs[len(gotArgs)-1]
+		if _, err := os.Stat(configPath); !os.IsNotExist(err) {
+			t.Errorf("expected temporary config %s to be removed", configPath)
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		execCommand = func(name string, args ...string) *exec.Cmd {
+			return exec.Command("sh", "-c", "echo 'FATAL[0000] decode config: unknown field'; exit 1")
+		}
+		err := r.Check(testConfig)
+		if !errors.Is(err, common.ErrInvalidConfig) {
+			t.Fatalf("expected ErrInvalidConfig, got %v", err)
+		}
+		var configErr *common.ConfigError
+		if !errors.As(err, &configErr) {
+			t.Fatalf("expected ConfigError, got %T", err)
+		}
+		if configErr.Stage != common.ConfigStageCheck {
+			t.Errorf("expected stage %q, got %q", common.ConfigStageCheck, configErr.Stage)
+		}
+		if len(configErr.Messages) != 1 || !strings.Contains(configErr.Messages[0], "unknown field") {
+			t.Errorf("unexpected messages: %v", configErr.Messages)
+		}
+	})
+
+	t.Run("missing executable", func(t *testing.T) {
+		execCommand = func(name string, args ...string) *exec.Cmd {
+			return exec.Command("/nonexistent/sing-box")
+		}
+		err := r.Check(testConfig)
+		if err == nil || errors.Is(err, common.ErrInvalidConfig) {
+			t.Fatalf("expected execution error, got %v", err)
+		}
+	})
+}
+
+func TestSingboxRunner_AssetsPath(t *testing.T) {
+	origExec := execCommand
+	defer func() { execCommand = origExec }()
+
+	var gotArgs [][]string
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		gotArgs = append(gotArgs, args)
+		return exec.Command("sh", "-c", "exit 0")
+	}
+
+	r, err := NewSingboxRunner("sing-box", "/var/lib/sing-box", logging.NewStdLogger())
+	if err != nil {
+		t.Fatalf("failed to create runner: %v", err)
+	}
+	if err := r.Check(testConfig); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if err := r.Start(testConfig); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer r.Stop()
+
+	if len(gotArgs) != 2 {
+		t.Fatalf("expected 2 commands, got %v", gotArgs)
+	}
+	for _, args := range gotArgs {
+		if !strings.Contains(strings.Join(args, " "), "-D /var/lib/sing-box -c ") {
+			t.Errorf("expected the assets path as working directory, got %v", args)
+		}
+	}
+}
+
 func TestSingboxRunner_RealBinary_Start_LogCapture(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	singboxPath, err := exec.LookPath(common.DefaultSingboxExecutablePath)
+	singboxPath, err := exec.LookPath(testSingboxExecutablePath)
 	if err != nil {
-		t.Skipf("sing-box binary not found at %s: %v", common.DefaultSingboxExecutablePath, err)
+		t.Skipf("sing-box binary not found at %s: %v", testSingboxExecutablePath, err)
 	}
 
 	testConfig, err := loadConfig()
@@ -737,7 +842,7 @@ func TestSingboxRunner_RealBinary_Start_LogCapture(t *testing.T) {
 	}
 
 	logger := logging.NewStdLogger()
-	runner, err := NewSingboxRunner(singboxPath, logger)
+	runner, err := NewSingboxRunner(singboxPath, "", logger)
 	if err != nil {
 		t.Fatalf("Failed to create runner: %v", err)
 	}
@@ -825,9 +930,9 @@ func TestSingboxRunner_RealBinary_Restart_EventReset(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	singboxPath, err := exec.LookPath(common.DefaultSingboxExecutablePath)
+	singboxPath, err := exec.LookPath(testSingboxExecutablePath)
 	if err != nil {
-		t.Skipf("sing-box binary not found at %s: %v", common.DefaultSingboxExecutablePath, err)
+		t.Skipf("sing-box binary not found at %s: %v", testSingboxExecutablePath, err)
 	}
 
 	validConfig, err := loadConfig()
@@ -836,7 +941,7 @@ func TestSingboxRunner_RealBinary_Restart_EventReset(t *testing.T) {
 	}
 
 	logger := logging.NewStdLogger()
-	runner, err := NewSingboxRunner(singboxPath, logger)
+	runner, err := NewSingboxRunner(singboxPath, "", logger)
 	if err != nil {
 		t.Fatalf("Failed to create runner: %v", err)
 	}
@@ -893,9 +998,9 @@ func TestSingboxRunner_RealBinary_Stop_Event(t *testing.T) {
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	singboxPath, err := exec.LookPath(common.DefaultSingboxExecutablePath)
+	singboxPath, err := exec.LookPath(testSingboxExecutablePath)
 	if err != nil {
-		t.Skipf("sing-box binary not found at %s: %v", common.DefaultSingboxExecutablePath, err)
+		t.Skipf("sing-box binary not found at %s: %v", testSingboxExecutablePath, err)
 	}
 
 	validConfig, err := loadConfig()
@@ -904,7 +1009,7 @@ func TestSingboxRunner_RealBinary_Stop_Event(t *testing.T) {
 	}
 
 	logger := logging.NewStdLogger()
-	runner, err := NewSingboxRunner(singboxPath, logger)
+	runner, err := NewSingboxRunner(singboxPath, "", logger)
 	if err != nil {
 		t.Fatalf("Failed to create runner: %v", err)
 	}
@@ -949,9 +1054,9 @@ func TestSingboxRunner_RealBinary_SubscribeLogs_MultipleSubscribers(t *testing.T
 		t.Skip("Skipping integration test in short mode")
 	}
 
-	singboxPath, err := exec.LookPath(common.DefaultSingboxExecutablePath)
+	singboxPath, err := exec.LookPath(testSingboxExecutablePath)
 	if err != nil {
-		t.Skipf("sing-box binary not found at %s: %v", common.DefaultSingboxExecutablePath, err)
+		t.Skipf("sing-box binary not found at %s: %v", testSingboxExecutablePath, err)
 	}
 
 	testConfig, err := loadConfig()
@@ -960,7 +1065,7 @@ func TestSingboxRunner_RealBinary_SubscribeLogs_MultipleSubscribers(t *testing.T
 	}
 
 	logger := logging.NewStdLogger()
-	runner, err := NewSingboxRunner(singboxPath, logger)
+	runner, err := NewSingboxRunner(singboxPath, "", logger)
 	if err != nil {
 		t.Fatalf("Failed to create runner: %v", err)
 	}
@@ -19,7 +19,7 @@ import (
 )
 
 func loadStatsConfig() (string, error) {
-	path := common.DefaultSingboxConfigPath
+	path := testSingboxConfigPath
 	data, err := assets.ConfigFS.ReadFile(path)
 	if err != nil {
 		return "", fmt.Errorf("%w (%s): %w", common.ErrFailedReadEmbeddedConfig, path, err)
@@ -121,12 +121,12 @@ func waitForPortToListen(host string, port int, timeout time.Duration) error {
 
 func newTestStatsRunner(t *testing.T) *SingboxRunner {
 	tl := logging.NewStdLogger()
-	exePath, err := exec.LookPath(common.DefaultSingboxExecutablePath)
+	exePath, err := exec.LookPath(testSingboxExecutablePath)
 	if err != nil {
 		t.Skipf("failed to find singbox executable: %v", err)
 	}
 
-	runner, err := NewSingboxRunner(exePath, tl)
+	runner, err := NewSingboxRunner(exePath, "", tl)
 	if err != nil {
 		t.Fatalf("failed to create runner: %v", err)
 	}
@@ -112,7 +112,7 @@ func TestSingboxRunner_Version(t *testing.T) {
 }
 
 func newVersionTestRunner(t *testing.T) *SingboxRunner {
-	exePath, err := exec.LookPath(common.DefaultSingboxExecutablePath)
+	exePath, err := exec.LookPath(testSingboxExecutablePath)
 	if err != nil {
 		t.Fatalf("failed to find singbox executable: %v", err)
 	}
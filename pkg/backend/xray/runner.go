@@ -2,6 +2,7 @@ package xray
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -113,11 +114,41 @@ func (r *XrayRunner) Restart(config string) error {
 	})
 }
 
-func (r *XrayRunner) Reload() error {
+func (r *XrayRunner) Reload(config string) error {
 	// Xray doesn't support reload on the fly
 	return nil
 }
 
+// Check runs xray in test mode against the config without starting it.
+func (r *XrayRunner) Check(config string) error {
+	configFile, err := os.CreateTemp(os.TempDir(), "xray-check-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary config file: %w", err)
+	}
+	defer os.Remove(configFile.Name())
+
+	if _, err := configFile.WriteString(config); err != nil {
+		configFile.Close()
+		return fmt.Errorf("failed to write config to file: %w", err)
+	}
+	if err := configFile.Close(); err != nil {
+		return fmt.Errorf("failed to close config file: %w", err)
+	}
+
+	cmd := execCommand(r.ExecutablePath, "run", "-test", "-config", configFile.Name())
+	cmd.Env = append(os.Environ(), "XRAY_LOCATION_ASSET="+r.assetsPath)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return common.NewCheckError(output)
+		}
+		return fmt.Errorf("failed to run xray test: %w", err)
+	}
+	return nil
+}
+
 func (r *XrayRunner) Stop() error {
 	if err := r.Controller.Stop(); err != nil {
 		return err
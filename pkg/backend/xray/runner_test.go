@@ -20,6 +20,12 @@ import (
 	"github.com/highlight-apps/node-backend/logging"
 )
 
+const (
+	testXrayExecutablePath = "testdata/xray"
+	testXrayConfigPath     = "xray_config.json"
+	testAssetsPath         = "assets"
+)
+
 func TestMain(m *testing.M) {
 	realExec := exec.Command
 	execCommand = func(name string, args ...string) *exec.Cmd {
@@ -34,12 +40,12 @@ func TestMain(m *testing.M) {
 func TestXrayRunner_New_Success(t *testing.T) {
 	tl := logging.NewStdLogger()
 
-	exePath, err := exec.LookPath(common.DefaultXrayExecutablePath)
+	exePath, err := exec.LookPath(testXrayExecutablePath)
 	if err != nil {
 		t.Fatalf("failed to find xray executable: %v", err)
 	}
 
-	assetsPath, err := filepath.Abs(filepath.Join("..", "..", common.DefaultAssetsPath))
+	assetsPath, err := filepath.Abs(filepath.Join("..", "..", testAssetsPath))
 	if err != nil {
 		t.Fatalf("failed to resolve assets path: %v", err)
 	}
@@ -51,11 +57,11 @@ func TestXrayRunner_New_Success(t *testing.T) {
 }
 
 func TestXrayRunner_New_DefaultLogger(t *testing.T) {
-	exePath, err := exec.LookPath(common.DefaultXrayExecutablePath)
+	exePath, err := exec.LookPath(testXrayExecutablePath)
 	if err != nil {
 		t.Fatalf("failed to find xray executable: %v", err)
 	}
-	assetsPath, err := filepath.Abs(filepath.Join("..", "..", common.DefaultAssetsPath))
+	assetsPath, err := filepath.Abs(filepath.Join("..", "..", testAssetsPath))
 	if err != nil {
 		t.Fatalf("failed to resolve assets path: %v", err)
 	}
@@ -100,11 +106,11 @@ func TestXrayRunner_New_AssetsPathSet(t *testing.T) {
 }
 
 func TestXrayRunner_New_ControllerSet(t *testing.T) {
-	exePath, err := exec.LookPath(common.DefaultXrayExecutablePath)
+	exePath, err := exec.LookPath(testXrayExecutablePath)
 	if err != nil {
 		t.Fatalf("failed to find xray executable: %v", err)
 	}
-	assetsPath, err := filepath.Abs(filepath.Join("..", "..", common.DefaultAssetsPath))
+	assetsPath, err := filepath.Abs(filepath.Join("..", "..", testAssetsPath))
 	if err != nil {
 		t.Fatalf("failed to resolve assets path: %v", err)
 	}
@@ -522,7 +528,7 @@ func TestXrayRunner_Restart_NotRunning(t *testing.T) {
 
 func TestXrayRunner_Reload_Success(t *testing.T) {
 	b := newTestRunner(t)
-	if err := b.Reload(); err != nil {
+	if err := b.Reload("config"); err != nil {
 		t.Errorf("expected no error from Reload, got %v", err)
 	}
 }
@@ -712,12 +718,12 @@ func (e errWriterCloser) Close() error                { return nil }
 
 func newTestRunner(t *testing.T) *XrayRunner {
 	tl := logging.NewStdLogger()
-	exePath, err := exec.LookPath(common.DefaultXrayExecutablePath)
+	exePath, err := exec.LookPath(testXrayExecutablePath)
 	if err != nil {
 		t.Fatalf("failed to find xray executable: %v", err)
 	}
 
-	assetsPath, err := filepath.Abs(filepath.Join("..", "..", common.DefaultAssetsPath))
+	assetsPath, err := filepath.Abs(filepath.Join("..", "..", testAssetsPath))
 	if err != nil {
 		t.Fatalf("failed to resolve assets path: %v", err)
 	}
@@ -731,7 +737,7 @@ func newTestRunner(t *testing.T) *XrayRunner {
 }
 
 func newTestRunnerWithLogger(t *testing.T, logger logging.Logger) *XrayRunner {
-	exePath, err := exec.LookPath(common.DefaultXrayExecutablePath)
+	exePath, err := exec.LookPath(testXrayExecutablePath)
 	if err != nil {
 		t.Fatalf("failed to find xray executable: %v", err)
 	}
@@ -758,7 +764,7 @@ func newTestRunnerWithWrongPath() *XrayRunner {
 
 func loadConfig() (string, error) {
 	var path string
-	path = common.DefaultXrayConfigPath
+	path = testXrayConfigPath
 
 	data, err := assets.ConfigFS.ReadFile(path)
 	if err != nil {